@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/multi-factor-authentication/activate-factor
+
+const (
+	// ActivateFactorURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/multi-factor-authentication/activate-factor
+	ActivateFactorURIPath = "api/1/users/%d/otp_devices/%d/trigger"
+)
+
+// ActivateFactorResult match the result of the end point requested
+type ActivateFactorResult struct {
+	Status ResultStatus
+	Data   []DeviceActivation `json:"data"`
+}
+
+// ActivateFactorRequest is the input request structure for this API call.
+type ActivateFactorRequest struct {
+}
+
+// NewActivateFactor return a new object ActivateFactorResult
+func NewActivateFactor() (ret *ActivateFactorResult) {
+	ret = new(ActivateFactorResult)
+	return
+}
+
+// Post the request as defined by the API
+// It sends the OTP code (SMS, push, ...) to the device. Verify it with VerifyEnrollmentResult.
+func (r *ActivateFactorResult) Post(a *Core, userID int64, deviceID int) (response *http.Response, err error) {
+	return r.PostWithContext(context.Background(), a, userID, deviceID)
+}
+
+// PostWithContext is Post cancelled when ctx is done.
+func (r *ActivateFactorResult) PostWithContext(ctx context.Context, a *Core, userID int64, deviceID int) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("ActivateFactorResult is nil")
+	}
+
+	response, err = a.requestRenewingToken(ctx, "POST", a.GetURL(ActivateFactorURIPath, userID, deviceID), ActivateFactorRequest{}, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/assign-role-to-user
+
+const (
+	// AddUserRolesURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/assign-role-to-user
+	AddUserRolesURIPath = "api/1/users/%d/add_roles"
+)
+
+// AddUserRolesResult match the result of the end point requested
+type AddUserRolesResult struct {
+	Status ResultStatus
+}
+
+// UserRolesRequest is the input request structure to add or remove user roles.
+type UserRolesRequest struct {
+	RolesID []int64 `json:"role_id_array"`
+}
+
+// NewAddUserRoles return a new object AddUserRolesResult
+func NewAddUserRoles() (ret *AddUserRolesResult) {
+	ret = new(AddUserRolesResult)
+	return
+}
+
+// Put the request as defined by the API
+func (r *AddUserRolesResult) Put(a *Core, id int64, rolesID []int64) (response *http.Response, err error) {
+	return r.PutWithContext(context.Background(), a, id, rolesID)
+}
+
+// PutWithContext is Put cancelled when ctx is done.
+func (r *AddUserRolesResult) PutWithContext(ctx context.Context, a *Core, id int64, rolesID []int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("AddUserRolesResult is nil")
+	}
+
+	input := UserRolesRequest{RolesID: rolesID}
+
+	response, err = a.requestRenewingToken(ctx, "PUT", a.GetURL(AddUserRolesURIPath, id), input, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -0,0 +1,51 @@
+package api
+
+import "time"
+
+// App contains OneLogin App definition.
+// See https://developers.onelogin.com/api-docs/1/apps/get-apps
+type App struct {
+	ID           int64  `json:"id"`
+	ConnectorID  int64  `json:"connector_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Extension    bool   `json:"extension"`
+	Icon         string `json:"icon"`
+	Visible      bool   `json:"visible"`
+	Provisioning bool   `json:"provisioning"`
+}
+
+// UserApp contains an App assigned to a user.
+// See https://developers.onelogin.com/api-docs/1/users/get-apps-for-user
+type UserApp struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	IconURL     string `json:"icon_url"`
+	LoginID     int64  `json:"login_id"`
+	Provisioned int    `json:"provisioned"`
+	Extension   bool   `json:"extension"`
+	Personal    bool   `json:"personal"`
+}
+
+// AppDetails contains the OneLogin App definition returned by the API v2.
+// See https://developers.onelogin.com/api-docs/2/apps/get-app
+type AppDetails struct {
+	ID           int64           `json:"id"`
+	ConnectorID  int64           `json:"connector_id"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	Notes        string          `json:"notes"`
+	IconURL      string          `json:"icon_url"`
+	Visible      bool            `json:"visible"`
+	AuthMethod   int             `json:"auth_method"`
+	PolicyID     int64           `json:"policy_id"`
+	RoleIDs      []int64         `json:"role_ids"`
+	Provisioning AppProvisioning `json:"provisioning"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// AppProvisioning is the provisioning state of an app.
+type AppProvisioning struct {
+	Enabled bool `json:"enabled"`
+}
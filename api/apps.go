@@ -0,0 +1,7 @@
+package api
+
+// Apps is a collection of App
+type Apps []App
+
+// UserApps is a collection of UserApp
+type UserApps []UserApp
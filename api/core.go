@@ -1,7 +1,12 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/clarsonneur/onelogin/common"
 )
@@ -11,6 +16,9 @@ const (
 	OneLoginURL                = "https://api.%s.onelogin.com"
 	TimeSleepOnResponsePending = 15
 	MaxIterGetSAMLResponse     = 6
+
+	// DefaultTokenRefreshMargin is the time before the token expiration when a new token is requested.
+	DefaultTokenRefreshMargin = 5 * time.Minute
 )
 
 // Core is the core API object
@@ -27,6 +35,17 @@ type Core struct {
 
 	// Token struct for managing the OAuth token
 	Token *OAuthTokenResult
+	// TokenRefreshMargin is the time before the token expiration when the token is renewed.
+	// If 0, DefaultTokenRefreshMargin is used.
+	TokenRefreshMargin time.Duration
+	tokenLock          sync.Mutex
+
+	// HTTPClient is the http client used by every API call. If nil, http.DefaultClient is used.
+	// Set it to configure timeouts, proxies, TLS or a dedicated transport.
+	HTTPClient *http.Client
+
+	// RetryPolicy define how calls are retried when rate limited or on temporary failures. nil disables retries.
+	RetryPolicy *RetryPolicy
 }
 
 // NewAPI create the main API object
@@ -55,12 +74,209 @@ func (o *Core) GetURL(uri string, args ...interface{}) string {
 
 // ObtainAPIAccess initialize the access to the API.
 func (o *Core) ObtainAPIAccess() (err error) {
-	o.Token = NewOAuthTokenResult()
+	return o.ObtainAPIAccessWithContext(context.Background())
+}
+
+// ObtainAPIAccessWithContext is ObtainAPIAccess cancelled when ctx is done.
+// A new token is always requested. Use EnsureAPIAccessWithContext to renew it only when needed.
+func (o *Core) ObtainAPIAccessWithContext(ctx context.Context) (err error) {
+	o.tokenLock.Lock()
+	defer o.tokenLock.Unlock()
+
+	return o.obtainToken(ctx)
+}
+
+// EnsureAPIAccess obtain a new token if there is none or if the current one expires within the TokenRefreshMargin.
+func (o *Core) EnsureAPIAccess() (err error) {
+	return o.EnsureAPIAccessWithContext(context.Background())
+}
+
+// EnsureAPIAccessWithContext is EnsureAPIAccess cancelled when ctx is done.
+func (o *Core) EnsureAPIAccessWithContext(ctx context.Context) (err error) {
+	_, err = o.accessToken(ctx)
+	return
+}
+
+// TokenExpiresAt return the expiration date of the current token. Zero if there is no token.
+func (o *Core) TokenExpiresAt() time.Time {
+	o.tokenLock.Lock()
+	defer o.tokenLock.Unlock()
+
+	return o.Token.ExpiresAt()
+}
+
+// TokenLifetime return the remaining time before the current token expires. 0 if there is no valid token.
+func (o *Core) TokenLifetime() time.Duration {
+	o.tokenLock.Lock()
+	defer o.tokenLock.Unlock()
+
+	return o.Token.Lifetime()
+}
+
+// obtainToken request a new token and replace the current one if the request succeed.
+// tokenLock must be held by the caller.
+func (o *Core) obtainToken(ctx context.Context) (err error) {
+	token := NewOAuthTokenResult()
+	if err = token.ObtainWithContext(ctx, o); err != nil {
+		return
+	}
+	o.Token = token
+	return
+}
+
+// accessToken return a valid access token, renewed before expiration.
+func (o *Core) accessToken(ctx context.Context) (_ string, err error) {
+	o.tokenLock.Lock()
+	defer o.tokenLock.Unlock()
+
+	margin := o.TokenRefreshMargin
+	if margin == 0 {
+		margin = DefaultTokenRefreshMargin
+	}
+	if o.Token.expiresWithin(margin) {
+		if err = o.obtainToken(ctx); err != nil {
+			return
+		}
+	}
+	return o.Token.AccessToken, nil
+}
+
+// renewToken obtain a new token if the given one is still the current one.
+// Used when the API rejects a token before its expected expiration.
+func (o *Core) renewToken(ctx context.Context, rejected string) (err error) {
+	o.tokenLock.Lock()
+	defer o.tokenLock.Unlock()
+
+	if o.Token != nil && o.Token.AccessToken != rejected {
+		// Already renewed by another call.
+		return
+	}
+	return o.obtainToken(ctx)
+}
+
+// SetHTTPClient define the http client used by every API call. nil restores http.DefaultClient.
+func (o *Core) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// SetTransport define the http RoundTripper used by every API call.
+// The current http client is copied so that a shared client (like http.DefaultClient) is never updated.
+func (o *Core) SetTransport(transport http.RoundTripper) {
+	client := new(http.Client)
+	if o.HTTPClient != nil {
+		*client = *o.HTTPClient
+	}
+	client.Transport = transport
+	o.HTTPClient = client
+}
+
+// GetHTTPClient return the http client used by API calls.
+func (o *Core) GetHTTPClient() *http.Client {
+	if o.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return o.HTTPClient
+}
+
+// SetRetryPolicy define how API calls are retried. nil disables retries.
+func (o *Core) SetRetryPolicy(policy *RetryPolicy) {
+	o.RetryPolicy = policy
+}
+
+// request execute an authenticated API call with the Core http client.
+// The access token is obtained or renewed if needed before the call. A rejected token (401) is returned as an
+// error: endpoints which can safely be called again use requestRenewingToken.
+func (o *Core) request(ctx context.Context, method, url string, input interface{}, data interface{}) (response *http.Response, err error) {
+	return o.doRequest(ctx, method, url, input, data, false)
+}
+
+// requestRenewingToken is request, but if the API rejects the token (401), it is renewed and the call is retried once.
+// It must not be used by endpoints reporting user failures with 401, like saml_assertion (bad credentials) or
+// verify_factor (invalid OTP): the user failure would be posted twice.
+func (o *Core) requestRenewingToken(ctx context.Context, method, url string, input interface{}, data interface{}) (response *http.Response, err error) {
+	return o.doRequest(ctx, method, url, input, data, true)
+}
+
+// doRequest execute the API call, retried once with a new token on 401 if renewOnUnauthorized is true.
+func (o *Core) doRequest(ctx context.Context, method, url string, input interface{}, data interface{}, renewOnUnauthorized bool) (response *http.Response, err error) {
+	response, body, err := o.authorizedDo(ctx, method, url, input, getBearerHeaders, renewOnUnauthorized)
+	if err != nil {
+		return
+	}
+
+	if len(body) == 0 {
+		// No content, like some DELETE responses.
+		return
+	}
+	if err = json.Unmarshal(body, data); err != nil && response.StatusCode >= 400 {
+		// Not a OneLogin status (proxy error page for example)
+		err = NewAPIError(response, ResultStatus{})
+	}
+	return
+}
+
+// requestV2 execute an authenticated call to the API v2, retried once with a new token on 401.
+// The API v2 expects an "Authorization: Bearer" header, returns the resource without status envelope, and reports
+// failures as {"statusCode": 404, "name": "NotFoundError", "message": "..."}, returned as an APIError.
+func (o *Core) requestV2(ctx context.Context, method, url string, input interface{}, data interface{}) (response *http.Response, err error) {
+	response, body, err := o.authorizedDo(ctx, method, url, input, getBearerHeadersV2, true)
+	if err != nil {
+		return
+	}
 
-	return o.Token.Obtain(o)
+	if response.StatusCode >= 400 {
+		// A body which is not a v2 error (proxy error page for example) gives an empty status.
+		failure := errorV2{}
+		json.Unmarshal(body, &failure)
+		return response, NewAPIError(response, failure.status())
+	}
+	if len(body) == 0 {
+		return
+	}
+	err = json.Unmarshal(body, data)
+	return
+}
+
+// errorV2 is the body of an API v2 failure.
+type errorV2 struct {
+	StatusCode int    `json:"statusCode"`
+	Name       string `json:"name"`
+	Message    string `json:"message"`
+}
+
+// status return the failure as a v1 ResultStatus.
+func (e errorV2) status() ResultStatus {
+	return ResultStatus{Error: true, Code: e.StatusCode, Type: e.Name, Message: e.Message}
+}
+
+// authorizedDo execute the API call with the access token in the headers built by headers.
+// If renewOnUnauthorized is true and the API rejects the token (401), it is renewed and the call is retried once.
+func (o *Core) authorizedDo(ctx context.Context, method, url string, input interface{}, headers func(token string) common.Headers, renewOnUnauthorized bool) (response *http.Response, body []byte, err error) {
+	for retried := false; ; retried = true {
+		var token string
+		if token, err = o.accessToken(ctx); err != nil {
+			return
+		}
+
+		response, body, err = o.do(ctx, method, headers(token), url, input, method != "POST")
+		if err != nil {
+			return
+		}
+		if response.StatusCode != http.StatusUnauthorized || !renewOnUnauthorized || retried {
+			return
+		}
+		if err = o.renewToken(ctx, token); err != nil {
+			return
+		}
+	}
+}
+
+func getBearerHeaders(token string) (ret common.Headers) {
+	ret = GetHeaders("bearer:" + token)
+	return
 }
 
-func (o *Core) getBearerHeaders() (ret common.Headers) {
-	ret = GetHeaders("bearer:" + o.Token.AccessToken)
+func getBearerHeadersV2(token string) (ret common.Headers) {
+	ret = GetHeaders("Bearer " + token)
 	return
 }
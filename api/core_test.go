@@ -0,0 +1,160 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubOneLogin is a OneLogin API stand-in. It delivers tokens "token-1", "token-2", ... and counts the calls by path.
+type stubOneLogin struct {
+	*httptest.Server
+
+	lock   sync.Mutex
+	tokens int
+	calls  map[string]int
+	// handler answers the API calls, except the token requests.
+	handler func(w http.ResponseWriter, r *http.Request, call int)
+}
+
+// newStubOneLogin starts the stub. Close it when done.
+func newStubOneLogin(handler func(w http.ResponseWriter, r *http.Request, call int)) (ret *stubOneLogin) {
+	ret = &stubOneLogin{calls: make(map[string]int), handler: handler}
+	ret.Server = httptest.NewServer(http.HandlerFunc(ret.serve))
+	return
+}
+
+func (s *stubOneLogin) serve(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	s.calls[r.URL.Path]++
+	call := s.calls[r.URL.Path]
+	if r.URL.Path == "/"+TokenURIPath {
+		s.tokens++
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":36000}`, s.tokens)
+		s.lock.Unlock()
+		return
+	}
+	s.lock.Unlock()
+	s.handler(w, r, call)
+}
+
+func (s *stubOneLogin) count(path string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.calls["/"+path]
+}
+
+func (s *stubOneLogin) core() *Core {
+	a := NewAPI("us", "id", "secret", "test")
+	a.CustomURL = s.URL
+	return a
+}
+
+func writeStatus(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	fmt.Fprintf(w, `{"status":{"error":%t,"code":%d,"type":"%s","message":"%s"}}`, code >= 400, code,
+		http.StatusText(code), message)
+}
+
+func TestRequestRenewingTokenOnUnauthorized(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		if r.Header.Get("Authorization") != "bearer:token-2" {
+			writeStatus(w, http.StatusUnauthorized, "Authentication Failure")
+			return
+		}
+		fmt.Fprint(w, `{"status":{"error":false,"code":200,"type":"success","message":"Success"},"data":[{"id":1,"name":"admin"}]}`)
+	})
+	defer stub.Close()
+	a := stub.core()
+
+	role := NewGetRoleByID()
+	if _, err := role.Get(a, 1); err != nil {
+		t.Fatalf("Get: %s", err)
+	}
+	if len(role.Data) != 1 || role.Data[0].Name != "admin" {
+		t.Errorf("role = %+v", role.Data)
+	}
+	if got := stub.count(TokenURIPath); got != 2 {
+		t.Errorf("token requests = %d, want 2", got)
+	}
+	if got := stub.count(fmt.Sprintf(GetRoleByIDURIPath, 1)); got != 2 {
+		t.Errorf("role requests = %d, want 2", got)
+	}
+}
+
+func TestRequestRenewingTokenOnlyOnce(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		writeStatus(w, http.StatusUnauthorized, "Authentication Failure")
+	})
+	defer stub.Close()
+
+	_, err := NewGetRoleByID().Get(stub.core(), 1)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+	if got := stub.count(fmt.Sprintf(GetRoleByIDURIPath, 1)); got != 2 {
+		t.Errorf("role requests = %d, want 2", got)
+	}
+}
+
+func TestSAMLAssertionUnauthorizedNotRetried(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		writeStatus(w, http.StatusUnauthorized, "Authentication Failed: Invalid user credentials")
+	})
+	defer stub.Close()
+
+	_, err := NewSAMLAssertionResult().Post(stub.core(), "user", "bad password", "1", "test", "")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+	if got := stub.count(SAMLAssertionURIPath); got != 1 {
+		t.Errorf("saml_assertion posted %d times, want 1", got)
+	}
+	if got := stub.count(TokenURIPath); got != 1 {
+		t.Errorf("token requests = %d, want 1", got)
+	}
+}
+
+func TestVerifyFactorInvalidOTPNotRetried(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		writeStatus(w, http.StatusUnauthorized, "Failed authentication with this factor")
+	})
+	defer stub.Close()
+
+	_, err := NewVerifyFactorResult().Post(stub.core(), "1", 2, "state", "123456", true)
+	if !errors.Is(err, ErrInvalidOTP) {
+		t.Errorf("err = %v, want ErrInvalidOTP", err)
+	}
+	if got := stub.count(VerifyFactorURIPath); got != 1 {
+		t.Errorf("verify_factor posted %d times, want 1", got)
+	}
+}
+
+func TestEnsureAPIAccessRenewsExpiringToken(t *testing.T) {
+	stub := newStubOneLogin(nil)
+	defer stub.Close()
+	a := stub.core()
+
+	if err := a.EnsureAPIAccess(); err != nil {
+		t.Fatalf("EnsureAPIAccess: %s", err)
+	}
+	if err := a.EnsureAPIAccess(); err != nil {
+		t.Fatalf("EnsureAPIAccess: %s", err)
+	}
+	if got := stub.count(TokenURIPath); got != 1 {
+		t.Errorf("token requests = %d, want 1 while the token is valid", got)
+	}
+
+	// The token expires within the refresh margin.
+	a.TokenRefreshMargin = a.TokenLifetime() + 1
+	if err := a.EnsureAPIAccess(); err != nil {
+		t.Fatalf("EnsureAPIAccess: %s", err)
+	}
+	if got := stub.count(TokenURIPath); got != 2 || !strings.HasSuffix(a.Token.AccessToken, "-2") {
+		t.Errorf("token requests = %d, token = %s, want a renewed token", got, a.Token.AccessToken)
+	}
+}
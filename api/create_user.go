@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/create-user
+
+const (
+	// CreateUserURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/create-user
+	CreateUserURIPath = "api/1/users"
+)
+
+// CreateUserResult match the result of the end point requested
+type CreateUserResult struct {
+	Status ResultStatus
+	Data   Users `json:"data"`
+}
+
+// CreateUserRequest is the input request structure for this API call.
+// Firstname, Lastname, Email and Username are required by OneLogin.
+type CreateUserRequest struct {
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	Firstname     string `json:"firstname"`
+	Lastname      string `json:"lastname"`
+	Department    string `json:"department,omitempty"`
+	Title         string `json:"title,omitempty"`
+	Company       string `json:"company,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	Comment       string `json:"comment,omitempty"`
+	MemberOf      string `json:"member_of,omitempty"`
+	GroupID       int64  `json:"group_id,omitempty"`
+	ManagerUserID int64  `json:"manager_user_id,omitempty"`
+}
+
+// NewCreateUserRequest return a CreateUserRequest built from the user fields.
+func NewCreateUserRequest(user User) (ret CreateUserRequest) {
+	ret.Username = user.Username
+	ret.Email = user.Email
+	ret.Firstname = user.Firstname
+	ret.Lastname = user.Lastname
+	ret.Department = user.Department
+	ret.Title = user.Title
+	ret.Company = user.Company
+	ret.Phone = user.Phone
+	ret.Comment = user.Comment
+	ret.MemberOf = user.MemberOf
+	ret.GroupID = user.GroupID
+	ret.ManagerUserID = user.ManagerUserID
+	return
+}
+
+// NewCreateUser return a new object CreateUserResult
+func NewCreateUser() (ret *CreateUserResult) {
+	ret = new(CreateUserResult)
+	return
+}
+
+// Post the request as defined by the API
+func (r *CreateUserResult) Post(a *Core, input CreateUserRequest) (response *http.Response, err error) {
+	return r.PostWithContext(context.Background(), a, input)
+}
+
+// PostWithContext is Post cancelled when ctx is done.
+func (r *CreateUserResult) PostWithContext(ctx context.Context, a *Core, input CreateUserRequest) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("CreateUserResult is nil")
+	}
+
+	response, err = a.requestRenewingToken(ctx, "POST", a.GetURL(CreateUserURIPath), input, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/delete-user
+
+const (
+	// DeleteUserByIDURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/delete-user
+	DeleteUserByIDURIPath = "api/1/users/%d"
+)
+
+// DeleteUserByIDResult match the result of the end point requested
+type DeleteUserByIDResult struct {
+	Status ResultStatus
+}
+
+// NewDeleteUserByID return a new object DeleteUserByIDResult
+func NewDeleteUserByID() (ret *DeleteUserByIDResult) {
+	ret = new(DeleteUserByIDResult)
+	return
+}
+
+// Delete the request as defined by the API
+func (r *DeleteUserByIDResult) Delete(a *Core, id int64) (response *http.Response, err error) {
+	return r.DeleteWithContext(context.Background(), a, id)
+}
+
+// DeleteWithContext is Delete cancelled when ctx is done.
+func (r *DeleteUserByIDResult) DeleteWithContext(ctx context.Context, a *Core, id int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("DeleteUserByIDResult is nil")
+	}
+
+	response, err = a.requestRenewingToken(ctx, "DELETE", a.GetURL(DeleteUserByIDURIPath, id), nil, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/multi-factor-authentication/enroll-factor
+
+const (
+	// EnrollFactorURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/multi-factor-authentication/enroll-factor
+	EnrollFactorURIPath = "api/1/users/%d/otp_devices"
+)
+
+// EnrollFactorResult match the result of the end point requested
+type EnrollFactorResult struct {
+	Status ResultStatus
+	Data   Devices `json:"data"`
+}
+
+// EnrollFactorRequest is the input request structure for this API call.
+type EnrollFactorRequest struct {
+	FactorID    int64  `json:"factor_id"`
+	DisplayName string `json:"display_name"`
+	// Number is the phone number of SMS factors.
+	Number string `json:"number,omitempty"`
+	// Verified enrolls the device as already verified. Used for SMS factors only.
+	Verified bool `json:"verified,omitempty"`
+}
+
+// NewEnrollFactor return a new object EnrollFactorResult
+func NewEnrollFactor() (ret *EnrollFactorResult) {
+	ret = new(EnrollFactorResult)
+	return
+}
+
+// Post the request as defined by the API
+func (r *EnrollFactorResult) Post(a *Core, userID int64, input EnrollFactorRequest) (response *http.Response, err error) {
+	return r.PostWithContext(context.Background(), a, userID, input)
+}
+
+// PostWithContext is Post cancelled when ctx is done.
+func (r *EnrollFactorResult) PostWithContext(ctx context.Context, a *Core, userID int64, input EnrollFactorRequest) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("EnrollFactorResult is nil")
+	}
+
+	response, err = a.requestRenewingToken(ctx, "POST", a.GetURL(EnrollFactorURIPath, userID), input, r)
+	return checkResponse(response, err, r.Status)
+}
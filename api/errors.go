@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RequestIDHeader is the response header giving the OneLogin request identifier.
+const RequestIDHeader = "X-Request-Id"
+
+// Sentinel errors. Use errors.Is to check the kind of an API error.
+var (
+	ErrUnauthorized = errors.New("onelogin: unauthorized")
+	ErrNotFound     = errors.New("onelogin: not found")
+	ErrRateLimited  = errors.New("onelogin: rate limited")
+	ErrMFARequired  = errors.New("onelogin: MFA required")
+	ErrInvalidOTP   = errors.New("onelogin: invalid OTP")
+)
+
+// APIError is the error returned when the OneLogin API reports a failure.
+// Use errors.As to get it, and errors.Is to compare it to sentinel errors (ErrUnauthorized, ErrNotFound, ...)
+type APIError struct {
+	// HTTPStatus is the http response status code.
+	HTTPStatus int
+	// Status is the status returned by OneLogin in the response body.
+	Status ResultStatus
+	// Endpoint is the method and path of the API called. Ex: "GET /api/1/users"
+	Endpoint string
+	// RequestID is the OneLogin request identifier, if given.
+	RequestID string
+
+	kind error
+}
+
+// NewAPIError creates an APIError from the API response and the status decoded from its body.
+func NewAPIError(response *http.Response, status ResultStatus) (ret *APIError) {
+	ret = new(APIError)
+	ret.Status = status
+	if response != nil {
+		ret.HTTPStatus = response.StatusCode
+		ret.RequestID = response.Header.Get(RequestIDHeader)
+		if response.Request != nil && response.Request.URL != nil {
+			ret.Endpoint = response.Request.Method + " " + response.Request.URL.Path
+		}
+	}
+	ret.kind = ret.classify()
+	return
+}
+
+// Error return the error message
+func (e *APIError) Error() string {
+	message := e.Status.Message
+	if message == "" {
+		message = http.StatusText(e.HTTPStatus)
+	}
+	ret := fmt.Sprintf("%d %s: %s", e.HTTPStatus, e.Status.Type, message)
+	if e.Endpoint != "" {
+		ret += " (" + e.Endpoint + ")"
+	}
+	if e.RequestID != "" {
+		ret += " [request id " + e.RequestID + "]"
+	}
+	return ret
+}
+
+// Unwrap return the sentinel error matching this error, if any.
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// classify return the sentinel error matching the http status, the endpoint and the OneLogin status.
+func (e *APIError) classify() error {
+	code := e.HTTPStatus
+	if code == 0 || code == http.StatusOK {
+		code = e.Status.Code
+	}
+	message := strings.ToLower(e.Status.Message)
+
+	switch {
+	case strings.Contains(message, "mfa is required"):
+		return ErrMFARequired
+	case strings.HasSuffix(e.Endpoint, VerifyFactorURIPath) && (code == http.StatusUnauthorized || code == http.StatusBadRequest):
+		return ErrInvalidOTP
+	case code == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package api
+
+import "time"
+
+// Event contains OneLogin Event definition.
+// See https://developers.onelogin.com/api-docs/1/events/event-resource
+type Event struct {
+	ID                   int64     `json:"id"`
+	CreatedAt            time.Time `json:"created_at"`
+	AccountID            int64     `json:"account_id"`
+	UserID               int64     `json:"user_id"`
+	UserName             string    `json:"user_name"`
+	EventTypeID          int64     `json:"event_type_id"`
+	Notes                string    `json:"notes"`
+	IPAddress            string    `json:"ipaddr"`
+	ActorUserID          int64     `json:"actor_user_id"`
+	ActorUserName        string    `json:"actor_user_name"`
+	ActorSystem          string    `json:"actor_system"`
+	AssumingActingUserID int64     `json:"assuming_acting_user_id"`
+	RoleID               int64     `json:"role_id"`
+	RoleName             string    `json:"role_name"`
+	AppID                int64     `json:"app_id"`
+	AppName              string    `json:"app_name"`
+	GroupID              int64     `json:"group_id"`
+	GroupName            string    `json:"group_name"`
+	OTPDeviceID          int64     `json:"otp_device_id"`
+	OTPDeviceName        string    `json:"otp_device_name"`
+	PolicyID             int64     `json:"policy_id"`
+	PolicyName           string    `json:"policy_name"`
+	CustomMessage        string    `json:"custom_message"`
+	OperationName        string    `json:"operation_name"`
+	ErrorDescription     string    `json:"error_description"`
+}
+
+// EventType contains OneLogin Event type definition.
+type EventType struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
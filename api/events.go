@@ -0,0 +1,7 @@
+package api
+
+// Events is a collection of Event
+type Events []Event
+
+// EventTypes is a collection of EventType
+type EventTypes []EventType
@@ -0,0 +1,39 @@
+package api
+
+// Factor is an authentication factor available for a user.
+// See https://developers.onelogin.com/api-docs/1/multi-factor-authentication/available-factors
+type Factor struct {
+	FactorID int64  `json:"factor_id"`
+	Name     string `json:"name"`
+}
+
+// Factors is a collection of Factor
+type Factors []Factor
+
+// Device is a MFA device enrolled by a user.
+// DeviceID and DeviceType match the SAMLAssertionDevice fields.
+// See https://developers.onelogin.com/api-docs/1/multi-factor-authentication/enrolled-factors
+type Device struct {
+	DeviceID        int    `json:"id"`
+	DeviceType      string `json:"type_display_name"`
+	AuthFactorName  string `json:"auth_factor_name"`
+	UserDisplayName string `json:"user_display_name"`
+	PhoneNumber     string `json:"phone_number"`
+	Active          bool   `json:"active"`
+	Default         bool   `json:"default"`
+	NeedsTrigger    bool   `json:"needs_trigger"`
+	// StateToken is given when the device is enrolled and need to be verified.
+	StateToken string `json:"state_token"`
+}
+
+// Devices is a collection of Device
+type Devices []Device
+
+// DeviceActivation is the result of a device activation. StateToken must be given to verify the activation.
+// See https://developers.onelogin.com/api-docs/1/multi-factor-authentication/activate-factor
+type DeviceActivation struct {
+	DeviceID   int    `json:"device_id"`
+	UserID     int64  `json:"user_id"`
+	StateToken string `json:"state_token"`
+	Active     bool   `json:"active"`
+}
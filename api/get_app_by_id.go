@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/2/apps/get-app
+
+const (
+	// GetAppByIDURIPath defined the API Path for such request.
+	// The API v1 has no such request. As defined by https://developers.onelogin.com/api-docs/2/apps/get-app
+	GetAppByIDURIPath = "api/2/apps/%d"
+)
+
+// GetAppByIDResult match the result of the end point requested.
+// The API v2 returns the app without status and data envelope. Failures are returned as APIError.
+type GetAppByIDResult struct {
+	App AppDetails
+}
+
+// NewGetAppByID return a new object GetAppByIDResult
+func NewGetAppByID() (ret *GetAppByIDResult) {
+	ret = new(GetAppByIDResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetAppByIDResult) Get(a *Core, id int64) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, id)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetAppByIDResult) GetWithContext(ctx context.Context, a *Core, id int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetAppByIDResult is nil")
+	}
+
+	*r = GetAppByIDResult{}
+
+	return a.requestV2(ctx, "GET", a.GetURL(GetAppByIDURIPath, id), nil, &r.App)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// appV2 is an API v2 get app response.
+const appV2 = `{
+  "id": 123456,
+  "connector_id": 50534,
+  "name": "Amazon Web Services (AWS) Multi Role",
+  "description": "",
+  "notes": "",
+  "policy_id": null,
+  "brand_id": null,
+  "icon_url": "https://cdn-shadow.onlgn.net/images/icons/square/aws/old_original.png?1421095823",
+  "visible": true,
+  "auth_method": 2,
+  "tab_id": 1234,
+  "created_at": "2019-11-05T15:30:28.000Z",
+  "updated_at": "2020-03-12T10:01:41.000Z",
+  "role_ids": [101, 102],
+  "allow_assumed_signin": false,
+  "provisioning": {"enabled": false},
+  "sso": {"metadata_url": "https://app.onelogin.com/saml/metadata/123456"},
+  "configuration": {"signature_algorithm": "SHA-256"},
+  "parameters": {"https://aws.amazon.com/SAML/Attributes/Role": {"label": "Role", "user_attribute_mappings": "_macro_"}}
+}`
+
+func TestGetAppByIDV2(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token-1" {
+			t.Errorf("Authorization = %s, want the v2 header 'Bearer token-1'", got)
+		}
+		fmt.Fprint(w, appV2)
+	})
+	defer stub.Close()
+
+	app := NewGetAppByID()
+	if _, err := app.Get(stub.core(), 123456); err != nil {
+		t.Fatalf("Get: %s", err)
+	}
+	if app.App.ID != 123456 || app.App.ConnectorID != 50534 || app.App.Name != "Amazon Web Services (AWS) Multi Role" ||
+		!app.App.Visible || app.App.AuthMethod != 2 || len(app.App.RoleIDs) != 2 || app.App.Provisioning.Enabled {
+		t.Errorf("app = %+v", app.App)
+	}
+	if !app.App.UpdatedAt.Equal(time.Date(2020, 3, 12, 10, 1, 41, 0, time.UTC)) {
+		t.Errorf("UpdatedAt = %s", app.App.UpdatedAt)
+	}
+	if got := stub.count(fmt.Sprintf(GetAppByIDURIPath, 123456)); got != 1 {
+		t.Errorf("app requests = %d, want 1", got)
+	}
+}
+
+func TestGetAppByIDV2Error(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"statusCode":404,"name":"NotFoundError","message":"App not found"}`)
+	})
+	defer stub.Close()
+
+	_, err := NewGetAppByID().Get(stub.core(), 1)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want a not found APIError", err)
+	}
+	if apiErr.Status.Type != "NotFoundError" || apiErr.Status.Message != "App not found" {
+		t.Errorf("status = %+v", apiErr.Status)
+	}
+}
+
+func TestGetAppByIDV2RenewsToken(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"statusCode":401,"name":"Unauthorized","message":"Authentication Failure"}`)
+			return
+		}
+		fmt.Fprint(w, appV2)
+	})
+	defer stub.Close()
+
+	app := NewGetAppByID()
+	if _, err := app.Get(stub.core(), 123456); err != nil {
+		t.Fatalf("Get: %s", err)
+	}
+	if got := stub.count(TokenURIPath); got != 2 {
+		t.Errorf("token requests = %d, want 2", got)
+	}
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// https://developers.onelogin.com/api-docs/1/apps/get-apps
+
+const (
+	// GetAppsURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/apps/get-apps
+	GetAppsURIPath = "api/1/apps"
+)
+
+// GetAppsResult match the result of the end point requested
+type GetAppsResult struct {
+	Status     ResultStatus
+	Pagination ResultPagination
+	Data       Apps `json:"data"`
+	url        *url.URL
+}
+
+// AppFilter define the filters of the Get Apps API. Zero values are not used.
+type AppFilter struct {
+	Name        string
+	ConnectorID int64
+	AuthMethod  string
+}
+
+// QueryOptions return the QueryOptions matching the filter.
+func (f AppFilter) QueryOptions() (ret *QueryOptions) {
+	ret = NewQueryOptions()
+	if f.Name != "" {
+		ret.AddFilterOn("name", f.Name)
+	}
+	if f.ConnectorID != 0 {
+		ret.AddFilterOn("connector_id", strconv.FormatInt(f.ConnectorID, 10))
+	}
+	if f.AuthMethod != "" {
+		ret.AddFilterOn("auth_method", f.AuthMethod)
+	}
+	return
+}
+
+// NewGetApps return a new object GetAppsResult
+func NewGetApps() (ret *GetAppsResult) {
+	ret = new(GetAppsResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetAppsResult) Get(a *Core, queryOptions *QueryOptions) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, queryOptions)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetAppsResult) GetWithContext(ctx context.Context, a *Core, queryOptions *QueryOptions) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetAppsResult is nil")
+	}
+
+	if r.url, err = listURL(a, queryOptions, GetAppsURIPath); err != nil {
+		return
+	}
+
+	r.reset()
+
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
+	return checkResponse(response, err, r.Status)
+}
+
+// Next return the next pagination result
+// if response and err is nil, then there is no more next page to get.
+func (r *GetAppsResult) Next(a *Core) (response *http.Response, err error) {
+	return r.NextWithContext(context.Background(), a)
+}
+
+// NextWithContext is Next cancelled when ctx is done.
+func (r *GetAppsResult) NextWithContext(ctx context.Context, a *Core) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetAppsResult is nil")
+	}
+
+	if r.Pagination.AfterCursor == "" || r.url == nil {
+		return
+	}
+
+	common.UpdateQuery(r.url, map[string]string{
+		"after_cursor": r.Pagination.AfterCursor},
+	)
+
+	r.reset()
+
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
+	return checkResponse(response, err, r.Status)
+}
+
+// Iterate return an iterator on all apps matching queryOptions, following pagination.
+func (r *GetAppsResult) Iterate(ctx context.Context, a *Core, queryOptions *QueryOptions) *AppIterator {
+	if r == nil {
+		return &AppIterator{failedIterator(errors.New("GetAppsResult is nil"))}
+	}
+	u, err := listURL(a, queryOptions, GetAppsURIPath)
+	if err != nil {
+		return &AppIterator{failedIterator(err)}
+	}
+	r.url = u
+	return &AppIterator{newIterator(ctx, a, u, r)}
+}
+
+func (r *GetAppsResult) reset() {
+	r.Status = ResultStatus{}
+	r.Data = nil
+	r.Pagination = ResultPagination{}
+}
+
+func (r *GetAppsResult) items() (ret []interface{}) {
+	ret = make([]interface{}, len(r.Data))
+	for index, app := range r.Data {
+		ret[index] = app
+	}
+	return
+}
+
+func (r *GetAppsResult) pagination() ResultPagination {
+	return r.Pagination
+}
+
+func (r *GetAppsResult) status() ResultStatus {
+	return r.Status
+}
+
+// AppIterator is an Iterator on apps.
+type AppIterator struct {
+	*Iterator
+}
+
+// App return the current app.
+func (i *AppIterator) App() (ret App) {
+	ret, _ = i.Item().(App)
+	return
+}
+
+// All return all remaining apps. See Iterator.All
+func (i *AppIterator) All(maxItems int) (ret Apps, err error) {
+	items, err := i.Iterator.All(maxItems)
+	ret = make(Apps, len(items))
+	for index, item := range items {
+		ret[index] = item.(App)
+	}
+	return
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/multi-factor-authentication/available-factors
+
+const (
+	// GetAuthFactorsURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/multi-factor-authentication/available-factors
+	GetAuthFactorsURIPath = "api/1/users/%d/auth_factors"
+)
+
+// GetAuthFactorsResult match the result of the end point requested
+type GetAuthFactorsResult struct {
+	Status ResultStatus
+	Data   struct {
+		AuthFactors Factors `json:"auth_factors"`
+	} `json:"data"`
+}
+
+// NewGetAuthFactors return a new object GetAuthFactorsResult
+func NewGetAuthFactors() (ret *GetAuthFactorsResult) {
+	ret = new(GetAuthFactorsResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetAuthFactorsResult) Get(a *Core, userID int64) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, userID)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetAuthFactorsResult) GetWithContext(ctx context.Context, a *Core, userID int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetAuthFactorsResult is nil")
+	}
+
+	r.Data.AuthFactors = nil
+
+	response, err = a.requestRenewingToken(ctx, "GET", a.GetURL(GetAuthFactorsURIPath, userID), nil, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/multi-factor-authentication/enrolled-factors
+
+const (
+	// GetEnrolledFactorsURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/multi-factor-authentication/enrolled-factors
+	GetEnrolledFactorsURIPath = "api/1/users/%d/otp_devices"
+)
+
+// GetEnrolledFactorsResult match the result of the end point requested
+type GetEnrolledFactorsResult struct {
+	Status ResultStatus
+	Data   struct {
+		OTPDevices Devices `json:"otp_devices"`
+	} `json:"data"`
+}
+
+// NewGetEnrolledFactors return a new object GetEnrolledFactorsResult
+func NewGetEnrolledFactors() (ret *GetEnrolledFactorsResult) {
+	ret = new(GetEnrolledFactorsResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetEnrolledFactorsResult) Get(a *Core, userID int64) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, userID)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetEnrolledFactorsResult) GetWithContext(ctx context.Context, a *Core, userID int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetEnrolledFactorsResult is nil")
+	}
+
+	r.Data.OTPDevices = nil
+
+	response, err = a.requestRenewingToken(ctx, "GET", a.GetURL(GetEnrolledFactorsURIPath, userID), nil, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/events/get-event-types
+
+const (
+	// GetEventTypesURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/events/get-event-types
+	GetEventTypesURIPath = "api/1/events/types"
+)
+
+// GetEventTypesResult match the result of the end point requested
+type GetEventTypesResult struct {
+	Status ResultStatus
+	Data   EventTypes `json:"data"`
+}
+
+// NewGetEventTypes return a new object GetEventTypesResult
+func NewGetEventTypes() (ret *GetEventTypesResult) {
+	ret = new(GetEventTypesResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetEventTypesResult) Get(a *Core) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetEventTypesResult) GetWithContext(ctx context.Context, a *Core) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetEventTypesResult is nil")
+	}
+
+	r.Data = nil
+
+	response, err = a.requestRenewingToken(ctx, "GET", a.GetURL(GetEventTypesURIPath), nil, r)
+	return checkResponse(response, err, r.Status)
+}
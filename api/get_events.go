@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// https://developers.onelogin.com/api-docs/1/events/get-events
+
+const (
+	// GetEventsURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/events/get-events
+	GetEventsURIPath = "api/1/events"
+)
+
+// GetEventsResult match the result of the end point requested
+type GetEventsResult struct {
+	Status     ResultStatus
+	Pagination ResultPagination
+	Data       Events `json:"data"`
+	url        *url.URL
+}
+
+// EventFilter define the filters of the Get Events API. Zero values are not used.
+type EventFilter struct {
+	EventTypeID int64
+	UserID      int64
+	Since       *time.Time
+	Until       *time.Time
+}
+
+// QueryOptions return the QueryOptions matching the filter.
+func (f EventFilter) QueryOptions() (ret *QueryOptions) {
+	ret = NewQueryOptions().Since(f.Since).Until(f.Until)
+	if f.EventTypeID != 0 {
+		ret.AddFilterOn("event_type_id", strconv.FormatInt(f.EventTypeID, 10))
+	}
+	if f.UserID != 0 {
+		ret.AddFilterOn("user_id", strconv.FormatInt(f.UserID, 10))
+	}
+	return
+}
+
+// NewGetEvents return a new object GetEventsResult
+func NewGetEvents() (ret *GetEventsResult) {
+	ret = new(GetEventsResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetEventsResult) Get(a *Core, queryOptions *QueryOptions) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, queryOptions)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetEventsResult) GetWithContext(ctx context.Context, a *Core, queryOptions *QueryOptions) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetEventsResult is nil")
+	}
+
+	if r.url, err = listURL(a, queryOptions, GetEventsURIPath); err != nil {
+		return
+	}
+
+	r.reset()
+
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
+	return checkResponse(response, err, r.Status)
+}
+
+// Next return the next pagination result
+// if response and err is nil, then there is no more next page to get.
+func (r *GetEventsResult) Next(a *Core) (response *http.Response, err error) {
+	return r.NextWithContext(context.Background(), a)
+}
+
+// NextWithContext is Next cancelled when ctx is done.
+func (r *GetEventsResult) NextWithContext(ctx context.Context, a *Core) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetEventsResult is nil")
+	}
+
+	if r.Pagination.AfterCursor == "" || r.url == nil {
+		return
+	}
+
+	common.UpdateQuery(r.url, map[string]string{
+		"after_cursor": r.Pagination.AfterCursor},
+	)
+
+	r.reset()
+
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
+	return checkResponse(response, err, r.Status)
+}
+
+// Iterate return an iterator on all events matching queryOptions, following pagination.
+func (r *GetEventsResult) Iterate(ctx context.Context, a *Core, queryOptions *QueryOptions) *EventIterator {
+	if r == nil {
+		return &EventIterator{failedIterator(errors.New("GetEventsResult is nil"))}
+	}
+	u, err := listURL(a, queryOptions, GetEventsURIPath)
+	if err != nil {
+		return &EventIterator{failedIterator(err)}
+	}
+	r.url = u
+	return &EventIterator{newIterator(ctx, a, u, r)}
+}
+
+func (r *GetEventsResult) reset() {
+	r.Status = ResultStatus{}
+	r.Data = nil
+	r.Pagination = ResultPagination{}
+}
+
+func (r *GetEventsResult) items() (ret []interface{}) {
+	ret = make([]interface{}, len(r.Data))
+	for index, event := range r.Data {
+		ret[index] = event
+	}
+	return
+}
+
+func (r *GetEventsResult) pagination() ResultPagination {
+	return r.Pagination
+}
+
+func (r *GetEventsResult) status() ResultStatus {
+	return r.Status
+}
+
+// EventIterator is an Iterator on events.
+type EventIterator struct {
+	*Iterator
+}
+
+// Event return the current event.
+func (i *EventIterator) Event() (ret Event) {
+	ret, _ = i.Item().(Event)
+	return
+}
+
+// All return all remaining events. See Iterator.All
+func (i *EventIterator) All(maxItems int) (ret Events, err error) {
+	items, err := i.Iterator.All(maxItems)
+	ret = make(Events, len(items))
+	for index, item := range items {
+		ret[index] = item.(Event)
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/groups/get-group-by-id
+
+const (
+	// GetGroupByIDURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/groups/get-group-by-id
+	GetGroupByIDURIPath = "api/1/groups/%d"
+)
+
+// GetGroupByIDResult match the result of the end point requested
+type GetGroupByIDResult struct {
+	Status ResultStatus
+	Data   Groups `json:"data"`
+}
+
+// NewGetGroupByID return a new object GetGroupByIDResult
+func NewGetGroupByID() (ret *GetGroupByIDResult) {
+	ret = new(GetGroupByIDResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetGroupByIDResult) Get(a *Core, id int64) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, id)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetGroupByIDResult) GetWithContext(ctx context.Context, a *Core, id int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetGroupByIDResult is nil")
+	}
+
+	r.Data = nil
+
+	response, err = a.requestRenewingToken(ctx, "GET", a.GetURL(GetGroupByIDURIPath, id), nil, r)
+	return checkResponse(response, err, r.Status)
+}
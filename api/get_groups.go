@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// https://developers.onelogin.com/api-docs/1/groups/get-groups
+
+const (
+	// GetGroupsURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/groups/get-groups
+	GetGroupsURIPath = "api/1/groups"
+)
+
+// GetGroupsResult match the result of the end point requested
+type GetGroupsResult struct {
+	Status     ResultStatus
+	Pagination ResultPagination
+	Data       Groups `json:"data"`
+	url        *url.URL
+}
+
+// GetGroupsRequest is the input request structure for this API call.
+type GetGroupsRequest struct {
+}
+
+// NewGetGroups return a new object GetGroupsResult
+func NewGetGroups() (ret *GetGroupsResult) {
+	ret = new(GetGroupsResult)
+	return
+}
+
+// Get the request as defined by the API
+// Only the first page of groups is returned. Use Next or Iterate to get all of them.
+func (r *GetGroupsResult) Get(a *Core) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetGroupsResult) GetWithContext(ctx context.Context, a *Core) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetGroupsResult is nil")
+	}
+
+	if r.url, err = url.Parse(a.GetURL(GetGroupsURIPath)); err != nil {
+		return
+	}
+
+	r.reset()
+
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
+	return checkResponse(response, err, r.Status)
+}
+
+// Next return the next pagination result
+// if response and err is nil, then there is no more next page to get.
+func (r *GetGroupsResult) Next(a *Core) (response *http.Response, err error) {
+	return r.NextWithContext(context.Background(), a)
+}
+
+// NextWithContext is Next cancelled when ctx is done.
+func (r *GetGroupsResult) NextWithContext(ctx context.Context, a *Core) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetGroupsResult is nil")
+	}
+
+	if r.Pagination.AfterCursor == "" || r.url == nil {
+		return
+	}
+
+	common.UpdateQuery(r.url, map[string]string{
+		"after_cursor": r.Pagination.AfterCursor},
+	)
+
+	r.reset()
+
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
+	return checkResponse(response, err, r.Status)
+}
+
+// Iterate return an iterator on all groups matching queryOptions, following pagination.
+func (r *GetGroupsResult) Iterate(ctx context.Context, a *Core, queryOptions *QueryOptions) *GroupIterator {
+	if r == nil {
+		return &GroupIterator{failedIterator(errors.New("GetGroupsResult is nil"))}
+	}
+	u, err := listURL(a, queryOptions, GetGroupsURIPath)
+	if err != nil {
+		return &GroupIterator{failedIterator(err)}
+	}
+	r.url = u
+	return &GroupIterator{newIterator(ctx, a, u, r)}
+}
+
+func (r *GetGroupsResult) reset() {
+	r.Status = ResultStatus{}
+	r.Data = nil
+	r.Pagination = ResultPagination{}
+}
+
+func (r *GetGroupsResult) items() (ret []interface{}) {
+	ret = make([]interface{}, len(r.Data))
+	for index, group := range r.Data {
+		ret[index] = group
+	}
+	return
+}
+
+func (r *GetGroupsResult) pagination() ResultPagination {
+	return r.Pagination
+}
+
+func (r *GetGroupsResult) status() ResultStatus {
+	return r.Status
+}
+
+// GroupIterator is an Iterator on groups.
+type GroupIterator struct {
+	*Iterator
+}
+
+// Group return the current group.
+func (i *GroupIterator) Group() (ret Group) {
+	ret, _ = i.Item().(Group)
+	return
+}
+
+// All return all remaining groups. See Iterator.All
+func (i *GroupIterator) All(maxItems int) (ret Groups, err error) {
+	items, err := i.Iterator.All(maxItems)
+	ret = make(Groups, len(items))
+	for index, item := range items {
+		ret[index] = item.(Group)
+	}
+	return
+}
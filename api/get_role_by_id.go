@@ -1,10 +1,9 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
-
-	"github.com/clarsonneur/onelogin/common"
 )
 
 // https://developers.onelogin.com/api-docs/1/roles/get-role-by-id
@@ -33,12 +32,17 @@ func NewGetRoleByID() (ret *GetRoleByIDResult) {
 
 // Get the request as defined by the API
 func (r *GetRoleByIDResult) Get(a *Core, id int64) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, id)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetRoleByIDResult) GetWithContext(ctx context.Context, a *Core, id int64) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("GetRoleByIDResult is nil")
 	}
 
 	input := GetRoleByIDResult{}
 
-	response, err = common.Request("GET", a.getBearerHeaders(), a.GetURL(GetRoleByIDURIPath, id), input, r)
+	response, err = a.requestRenewingToken(ctx, "GET", a.GetURL(GetRoleByIDURIPath, id), input, r)
 	return checkResponse(response, err, r.Status)
 }
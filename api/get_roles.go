@@ -1,8 +1,10 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"net/url"
 
 	"github.com/clarsonneur/onelogin/common"
 )
@@ -17,8 +19,10 @@ const (
 
 // GetRolesResult match the result of the end point requested
 type GetRolesResult struct {
-	Status ResultStatus
-	Data   Roles `json:"data"`
+	Status     ResultStatus
+	Pagination ResultPagination
+	Data       Roles `json:"data"`
+	url        *url.URL
 }
 
 // GetRolesRequest is the input request structure for this API call.
@@ -32,13 +36,105 @@ func NewGetRoles() (ret *GetRolesResult) {
 }
 
 // Get the request as defined by the API
+// Only the first page of roles is returned. Use Next or Iterate to get all of them.
 func (r *GetRolesResult) Get(a *Core) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetRolesResult) GetWithContext(ctx context.Context, a *Core) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("GetRolesResult is nil")
 	}
 
-	input := GetRolesResult{}
+	if r.url, err = url.Parse(a.GetURL(GetRolesURIPath)); err != nil {
+		return
+	}
+
+	r.reset()
 
-	response, err = common.Request("GET", a.getBearerHeaders(), a.GetURL(GetRolesURIPath), input, r)
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
 	return checkResponse(response, err, r.Status)
 }
+
+// Next return the next pagination result
+// if response and err is nil, then there is no more next page to get.
+func (r *GetRolesResult) Next(a *Core) (response *http.Response, err error) {
+	return r.NextWithContext(context.Background(), a)
+}
+
+// NextWithContext is Next cancelled when ctx is done.
+func (r *GetRolesResult) NextWithContext(ctx context.Context, a *Core) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetRolesResult is nil")
+	}
+
+	if r.Pagination.AfterCursor == "" || r.url == nil {
+		return
+	}
+
+	common.UpdateQuery(r.url, map[string]string{
+		"after_cursor": r.Pagination.AfterCursor},
+	)
+
+	r.reset()
+
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
+	return checkResponse(response, err, r.Status)
+}
+
+// Iterate return an iterator on all roles matching queryOptions, following pagination.
+func (r *GetRolesResult) Iterate(ctx context.Context, a *Core, queryOptions *QueryOptions) *RoleIterator {
+	if r == nil {
+		return &RoleIterator{failedIterator(errors.New("GetRolesResult is nil"))}
+	}
+	u, err := listURL(a, queryOptions, GetRolesURIPath)
+	if err != nil {
+		return &RoleIterator{failedIterator(err)}
+	}
+	r.url = u
+	return &RoleIterator{newIterator(ctx, a, u, r)}
+}
+
+func (r *GetRolesResult) reset() {
+	r.Status = ResultStatus{}
+	r.Data = nil
+	r.Pagination = ResultPagination{}
+}
+
+func (r *GetRolesResult) items() (ret []interface{}) {
+	ret = make([]interface{}, len(r.Data))
+	for index, role := range r.Data {
+		ret[index] = role
+	}
+	return
+}
+
+func (r *GetRolesResult) pagination() ResultPagination {
+	return r.Pagination
+}
+
+func (r *GetRolesResult) status() ResultStatus {
+	return r.Status
+}
+
+// RoleIterator is an Iterator on roles.
+type RoleIterator struct {
+	*Iterator
+}
+
+// Role return the current role.
+func (i *RoleIterator) Role() (ret Role) {
+	ret, _ = i.Item().(Role)
+	return
+}
+
+// All return all remaining roles. See Iterator.All
+func (i *RoleIterator) All(maxItems int) (ret Roles, err error) {
+	items, err := i.Iterator.All(maxItems)
+	ret = make(Roles, len(items))
+	for index, item := range items {
+		ret[index] = item.(Role)
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/get-apps-for-user
+
+const (
+	// GetUserAppsURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/get-apps-for-user
+	GetUserAppsURIPath = "api/1/users/%d/apps"
+)
+
+// GetUserAppsResult match the result of the end point requested
+type GetUserAppsResult struct {
+	Status ResultStatus
+	Data   UserApps `json:"data"`
+}
+
+// NewGetUserApps return a new object GetUserAppsResult
+func NewGetUserApps() (ret *GetUserAppsResult) {
+	ret = new(GetUserAppsResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetUserAppsResult) Get(a *Core, id int64) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, id)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetUserAppsResult) GetWithContext(ctx context.Context, a *Core, id int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetUserAppsResult is nil")
+	}
+
+	r.Data = nil
+
+	response, err = a.requestRenewingToken(ctx, "GET", a.GetURL(GetUserAppsURIPath, id), nil, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -1,10 +1,9 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
-
-	"github.com/clarsonneur/onelogin/common"
 )
 
 // https://developers.onelogin.com/api-docs/1/users/get-user-by-id
@@ -33,12 +32,17 @@ func NewGetUserByID() (ret *GetUserByIDResult) {
 
 // Get the request as defined by the API
 func (r *GetUserByIDResult) Get(a *Core, id int64) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, id)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetUserByIDResult) GetWithContext(ctx context.Context, a *Core, id int64) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("GetUserByIDResult is nil")
 	}
 
 	input := GetUserByIDResult{}
 
-	response, err = common.Request("GET", a.getBearerHeaders(), a.GetURL(GetUserByIDURIPath, id), input, r)
+	response, err = a.requestRenewingToken(ctx, "GET", a.GetURL(GetUserByIDURIPath, id), input, r)
 	return checkResponse(response, err, r.Status)
 }
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/get-user-roles
+
+const (
+	// GetUserRolesURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/get-user-roles
+	GetUserRolesURIPath = "api/1/users/%d/roles"
+)
+
+// GetUserRolesResult match the result of the end point requested
+type GetUserRolesResult struct {
+	Status ResultStatus
+	Data   [][]int64 `json:"data"`
+}
+
+// NewGetUserRoles return a new object GetUserRolesResult
+func NewGetUserRoles() (ret *GetUserRolesResult) {
+	ret = new(GetUserRolesResult)
+	return
+}
+
+// Get the request as defined by the API
+func (r *GetUserRolesResult) Get(a *Core, id int64) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, id)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetUserRolesResult) GetWithContext(ctx context.Context, a *Core, id int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("GetUserRolesResult is nil")
+	}
+
+	r.Data = nil
+
+	response, err = a.requestRenewingToken(ctx, "GET", a.GetURL(GetUserRolesURIPath, id), nil, r)
+	return checkResponse(response, err, r.Status)
+}
+
+// RolesID return the list of role IDs assigned to the user.
+func (r *GetUserRolesResult) RolesID() (ret []int64) {
+	if r == nil {
+		return
+	}
+	for _, roles := range r.Data {
+		ret = append(ret, roles...)
+	}
+	return
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/url"
@@ -36,6 +37,11 @@ func NewGetUsers() (ret *GetUsersResult) {
 
 // Get the request as defined by the API
 func (r *GetUsersResult) Get(a *Core, queryOptions *QueryOptions) (response *http.Response, err error) {
+	return r.GetWithContext(context.Background(), a, queryOptions)
+}
+
+// GetWithContext is Get cancelled when ctx is done.
+func (r *GetUsersResult) GetWithContext(ctx context.Context, a *Core, queryOptions *QueryOptions) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("GetUsersResult is nil")
 	}
@@ -50,13 +56,18 @@ func (r *GetUsersResult) Get(a *Core, queryOptions *QueryOptions) (response *htt
 	r.Data = nil
 	r.Pagination = ResultPagination{}
 
-	response, err = common.Request("GET", a.getBearerHeaders(), r.url.String(), nil, r)
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
 	return checkResponse(response, err, r.Status)
 }
 
 // Next return the next pagination result
 // if response and err is nil, then there is no more next page to get.
 func (r *GetUsersResult) Next(a *Core) (response *http.Response, err error) {
+	return r.NextWithContext(context.Background(), a)
+}
+
+// NextWithContext is Next cancelled when ctx is done.
+func (r *GetUsersResult) NextWithContext(ctx context.Context, a *Core) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("GetUsersResult is nil")
 	}
@@ -74,6 +85,62 @@ func (r *GetUsersResult) Next(a *Core) (response *http.Response, err error) {
 	r.Data = nil
 	r.Pagination = ResultPagination{}
 
-	response, err = common.Request("GET", a.getBearerHeaders(), r.url.String(), nil, r)
+	response, err = a.requestRenewingToken(ctx, "GET", r.url.String(), nil, r)
 	return checkResponse(response, err, r.Status)
 }
+
+// Iterate return an iterator on all users matching queryOptions, following pagination.
+func (r *GetUsersResult) Iterate(ctx context.Context, a *Core, queryOptions *QueryOptions) *UserIterator {
+	if r == nil {
+		return &UserIterator{failedIterator(errors.New("GetUsersResult is nil"))}
+	}
+	u, err := listURL(a, queryOptions, GetUsersURIPath)
+	if err != nil {
+		return &UserIterator{failedIterator(err)}
+	}
+	r.url = u
+	return &UserIterator{newIterator(ctx, a, u, r)}
+}
+
+func (r *GetUsersResult) reset() {
+	r.Status = ResultStatus{}
+	r.Data = nil
+	r.Pagination = ResultPagination{}
+}
+
+func (r *GetUsersResult) items() (ret []interface{}) {
+	ret = make([]interface{}, len(r.Data))
+	for index, user := range r.Data {
+		ret[index] = user
+	}
+	return
+}
+
+func (r *GetUsersResult) pagination() ResultPagination {
+	return r.Pagination
+}
+
+func (r *GetUsersResult) status() ResultStatus {
+	return r.Status
+}
+
+// UserIterator is an Iterator on users.
+type UserIterator struct {
+	*Iterator
+}
+
+// User return the current user.
+func (i *UserIterator) User() (ret User) {
+	ret, _ = i.Item().(User)
+	return
+}
+
+// All return all remaining users. See Iterator.All
+func (i *UserIterator) All(maxItems int) (ret Users, err error) {
+	items, err := i.Iterator.All(maxItems)
+	ret = make(Users, len(items))
+	for index, item := range items {
+		ret[index] = item.(User)
+	}
+	return
+}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"fmt"
 	"net/http"
 
 	"github.com/clarsonneur/onelogin/common"
@@ -23,6 +22,7 @@ func GetHeaders(authorization string) common.Headers {
 	}
 }
 
+// checkResponse return an APIError if the API reported an error through the http status or the result status.
 func checkResponse(response *http.Response, inputErr error, status ResultStatus) (ret *http.Response, err error) {
 	ret = response
 	err = inputErr
@@ -30,8 +30,8 @@ func checkResponse(response *http.Response, inputErr error, status ResultStatus)
 		return
 	}
 
-	if status.Error {
-		err = fmt.Errorf("%d %s: %s", response.StatusCode, status.Type, status.Message)
+	if status.Error || (response != nil && response.StatusCode >= 400) {
+		err = NewAPIError(response, status)
 	}
 	return
 }
@@ -0,0 +1,8 @@
+package api
+
+// Group contains OneLogin Group definition.
+type Group struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Reference string `json:"reference"`
+}
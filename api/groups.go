@@ -0,0 +1,4 @@
+package api
+
+// Groups is a collection of Group
+type Groups []Group
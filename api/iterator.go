@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// ErrMaxItemsExceeded is returned by Iterator.All when the list has more items than the maximum given.
+var ErrMaxItemsExceeded = errors.New("onelogin: maximum number of items exceeded")
+
+// page is implemented by list results which can be iterated.
+type page interface {
+	// reset cleanup the page before reading a new one.
+	reset()
+	items() []interface{}
+	pagination() ResultPagination
+	status() ResultStatus
+}
+
+// Iterator walks through the items of a paginated list endpoint, following the pagination cursors.
+//
+//	it := api.NewGetUsers().Iterate(ctx, olAPI, nil)
+//	for it.Next() {
+//		user := it.User()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	ctx     context.Context
+	core    *Core
+	url     *url.URL
+	page    page
+	items   []interface{}
+	index   int
+	started bool
+	cursor  string
+	err     error
+}
+
+// newIterator creates an Iterator reading pages from the url into p.
+func newIterator(ctx context.Context, a *Core, u *url.URL, p page) (ret *Iterator) {
+	ret = new(Iterator)
+	ret.ctx = ctx
+	ret.core = a
+	ret.url = u
+	ret.page = p
+	ret.index = -1
+	return
+}
+
+// Next moves to the next item, reading the next page when needed.
+// It returns false when there is no more items or when an error occurred. Check Err() in that case.
+func (i *Iterator) Next() bool {
+	if i == nil || i.err != nil {
+		return false
+	}
+	i.index++
+	for i.index >= len(i.items) {
+		if i.started && i.cursor == "" {
+			return false
+		}
+		if !i.fetch() {
+			return false
+		}
+	}
+	return true
+}
+
+// Item return the current item.
+func (i *Iterator) Item() interface{} {
+	if i == nil || i.index < 0 || i.index >= len(i.items) {
+		return nil
+	}
+	return i.items[i.index]
+}
+
+// Err return the error which stopped the iteration, if any.
+func (i *Iterator) Err() error {
+	if i == nil {
+		return errors.New("Iterator is nil")
+	}
+	return i.err
+}
+
+// Cursor return the cursor of the next page to read. Empty if the last page has been read.
+func (i *Iterator) Cursor() string {
+	if i == nil {
+		return ""
+	}
+	return i.cursor
+}
+
+// All return all remaining items.
+// If maxItems > 0 and the list has more items, the items read are returned with ErrMaxItemsExceeded.
+func (i *Iterator) All(maxItems int) (ret []interface{}, err error) {
+	for i.Next() {
+		if maxItems > 0 && len(ret) >= maxItems {
+			return ret, fmt.Errorf("more than %d items: %w", maxItems, ErrMaxItemsExceeded)
+		}
+		ret = append(ret, i.Item())
+	}
+	err = i.Err()
+	return
+}
+
+// fetch read the next page.
+func (i *Iterator) fetch() bool {
+	if i.core == nil {
+		i.err = errors.New("Iterator: api.Core is nil")
+		return false
+	}
+	if i.started {
+		common.UpdateQuery(i.url, map[string]string{
+			"after_cursor": i.cursor},
+		)
+	}
+
+	i.page.reset()
+	response, err := i.core.requestRenewingToken(i.ctx, "GET", i.url.String(), nil, i.page)
+	if _, i.err = checkResponse(response, err, i.page.status()); i.err != nil {
+		return false
+	}
+
+	i.started = true
+	i.items = i.page.items()
+	i.index = 0
+	i.cursor = i.page.pagination().AfterCursor
+	return true
+}
+
+// listURL return the url of a list endpoint with the query options.
+func listURL(a *Core, queryOptions *QueryOptions, uri string, args ...interface{}) (ret *url.URL, err error) {
+	ret, err = url.Parse(a.GetURL(uri, args...))
+	if err != nil {
+		return
+	}
+	if queryOptions != nil {
+		common.SetQuery(ret, queryOptions.getQueryParameters())
+	}
+	return
+}
+
+// failedIterator return an Iterator which only reports the given error.
+func failedIterator(err error) *Iterator {
+	return &Iterator{err: err, index: -1}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// rolePages answers the roles list with 3 pages of 2 roles, linked by the cursors "page-2" and "page-3".
+func rolePages(t *testing.T) func(w http.ResponseWriter, r *http.Request, call int) {
+	return func(w http.ResponseWriter, r *http.Request, call int) {
+		page := 1
+		switch cursor := r.URL.Query().Get("after_cursor"); cursor {
+		case "":
+		case "page-2":
+			page = 2
+		case "page-3":
+			page = 3
+		default:
+			t.Errorf("unexpected cursor '%s'", cursor)
+		}
+		next := "null"
+		if page < 3 {
+			next = fmt.Sprintf(`"page-%d"`, page+1)
+		}
+		fmt.Fprintf(w, `{"status":{"error":false,"code":200,"type":"success","message":"Success"},`+
+			`"pagination":{"before_cursor":null,"after_cursor":%s},`+
+			`"data":[{"id":%d,"name":"role-%d"},{"id":%d,"name":"role-%d"}]}`,
+			next, page*2-1, page*2-1, page*2, page*2)
+	}
+}
+
+func TestIteratorFollowsCursors(t *testing.T) {
+	stub := newStubOneLogin(rolePages(t))
+	defer stub.Close()
+
+	it := NewGetRoles().Iterate(context.Background(), stub.core(), nil)
+	var ids []int
+	for it.Next() {
+		ids = append(ids, int(it.Role().ID))
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %s", err)
+	}
+	if fmt.Sprint(ids) != "[1 2 3 4 5 6]" {
+		t.Errorf("ids = %v, want [1 2 3 4 5 6]", ids)
+	}
+	if got := stub.count(GetRolesURIPath); got != 3 {
+		t.Errorf("pages read = %d, want 3", got)
+	}
+	if it.Next() {
+		t.Error("Next = true after the last item")
+	}
+}
+
+func TestIteratorAll(t *testing.T) {
+	stub := newStubOneLogin(rolePages(t))
+	defer stub.Close()
+
+	roles, err := NewGetRoles().Iterate(context.Background(), stub.core(), nil).All(0)
+	if err != nil {
+		t.Fatalf("All: %s", err)
+	}
+	if len(roles) != 6 || roles[5].Name != "role-6" {
+		t.Errorf("roles = %+v, want 6 roles", roles)
+	}
+}
+
+func TestIteratorAllMaxItems(t *testing.T) {
+	stub := newStubOneLogin(rolePages(t))
+	defer stub.Close()
+
+	roles, err := NewGetRoles().Iterate(context.Background(), stub.core(), nil).All(3)
+	if !errors.Is(err, ErrMaxItemsExceeded) {
+		t.Errorf("err = %v, want ErrMaxItemsExceeded", err)
+	}
+	if len(roles) != 3 {
+		t.Errorf("roles = %d, want the 3 first roles", len(roles))
+	}
+	if got := stub.count(GetRolesURIPath); got != 2 {
+		t.Errorf("pages read = %d, want 2", got)
+	}
+
+	stub6 := newStubOneLogin(rolePages(t))
+	defer stub6.Close()
+	if roles, err = NewGetRoles().Iterate(context.Background(), stub6.core(), nil).All(6); err != nil {
+		t.Errorf("All(6): %s", err)
+	}
+	if len(roles) != 6 {
+		t.Errorf("roles = %d, want 6", len(roles))
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			rolePages(t)(w, r, call)
+			return
+		}
+		writeStatus(w, http.StatusNotFound, "Not Found")
+	})
+	defer stub.Close()
+
+	it := NewGetRoles().Iterate(context.Background(), stub.core(), nil)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if count != 2 || !errors.Is(it.Err(), ErrNotFound) {
+		t.Errorf("count = %d, err = %v, want 2 and ErrNotFound", count, it.Err())
+	}
+}
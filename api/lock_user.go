@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/lock-user-account
+
+const (
+	// LockUserURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/lock-user-account
+	LockUserURIPath = "api/1/users/%d/lock_user"
+)
+
+// LockUserResult match the result of the end point requested
+type LockUserResult struct {
+	Status ResultStatus
+}
+
+// LockUserRequest is the input request structure for this API call.
+type LockUserRequest struct {
+	// LockedUntil is the number of minutes the user is locked. 0 uses the lock duration of the user policy.
+	LockedUntil int `json:"locked_until"`
+}
+
+// NewLockUser return a new object LockUserResult
+func NewLockUser() (ret *LockUserResult) {
+	ret = new(LockUserResult)
+	return
+}
+
+// Put the request as defined by the API
+func (r *LockUserResult) Put(a *Core, id int64, input LockUserRequest) (response *http.Response, err error) {
+	return r.PutWithContext(context.Background(), a, id, input)
+}
+
+// PutWithContext is Put cancelled when ctx is done.
+func (r *LockUserResult) PutWithContext(ctx context.Context, a *Core, id int64, input LockUserRequest) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("LockUserResult is nil")
+	}
+
+	response, err = a.requestRenewingToken(ctx, "PUT", a.GetURL(LockUserURIPath, id), input, r)
+	return checkResponse(response, err, r.Status)
+}
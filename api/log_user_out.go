@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/log-user-out
+
+const (
+	// LogUserOutURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/log-user-out
+	LogUserOutURIPath = "api/1/users/%d/logout"
+)
+
+// LogUserOutResult match the result of the end point requested
+type LogUserOutResult struct {
+	Status ResultStatus
+}
+
+// LogUserOutRequest is the input request structure for this API call.
+type LogUserOutRequest struct {
+}
+
+// NewLogUserOut return a new object LogUserOutResult
+func NewLogUserOut() (ret *LogUserOutResult) {
+	ret = new(LogUserOutResult)
+	return
+}
+
+// Put the request as defined by the API
+func (r *LogUserOutResult) Put(a *Core, id int64) (response *http.Response, err error) {
+	return r.PutWithContext(context.Background(), a, id)
+}
+
+// PutWithContext is Put cancelled when ctx is done.
+func (r *LogUserOutResult) PutWithContext(ctx context.Context, a *Core, id int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("LogUserOutResult is nil")
+	}
+
+	response, err = a.requestRenewingToken(ctx, "PUT", a.GetURL(LogUserOutURIPath, id), LogUserOutRequest{}, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"github.com/clarsonneur/onelogin/common"
+	"net/http"
 	"time"
 )
 
@@ -20,6 +22,9 @@ type OAuthTokenResult struct {
 	ExpiresIn   int    `json:"expires_in"`
 	TokenType   string `json:"token_type"`
 	AccountID   int    `json:"account_id"`
+
+	// obtainedAt is the local time when the token was received. Used if CreatedAt cannot be interpreted.
+	obtainedAt time.Time
 }
 
 // TokenRequest map auth/oauth2/v2/token request
@@ -35,14 +40,19 @@ func NewOAuthTokenResult() (ret *OAuthTokenResult) {
 
 // Obtain get or refresh a token to manage the OneLogin API
 func (t *OAuthTokenResult) Obtain(a *Core) (err error) {
+	return t.ObtainWithContext(context.Background(), a)
+}
+
+// ObtainWithContext is Obtain cancelled when ctx is done.
+func (t *OAuthTokenResult) ObtainWithContext(ctx context.Context, a *Core) (err error) {
 	if t.isExpired() {
-		return t.getToken(a)
+		return t.getToken(ctx, a)
 	}
 	// No need to refresh it
 	return nil
 }
 
-func (t *OAuthTokenResult) getToken(a *Core) (err error) {
+func (t *OAuthTokenResult) getToken(ctx context.Context, a *Core) (err error) {
 	url := a.GetURL(TokenURIPath)
 
 	input := TokenRequest{
@@ -53,30 +63,67 @@ func (t *OAuthTokenResult) getToken(a *Core) (err error) {
 	authorization := base64.StdEncoding.EncodeToString([]byte(a.ClientID + ":" + a.ClientSecret))
 	headers := GetHeaders("Basic " + authorization)
 
-	_, err = common.Request("POST", headers, url, input, t)
+	// Requesting a new token has no side effect. So, it can be retried.
+	var (
+		body     []byte
+		response *http.Response
+	)
+	if response, body, err = a.do(ctx, "POST", headers, url, input, true); err != nil {
+		return
+	}
+	t.obtainedAt = time.Now()
 
-	if t.ResultStatus.Error {
-		err = fmt.Errorf("APIToken error: %s", t.ResultStatus.Message)
+	if err = json.Unmarshal(body, t); t.ResultStatus.Error || response.StatusCode >= 400 {
+		return fmt.Errorf("APIToken error: %w", NewAPIError(response, t.ResultStatus))
+	}
+	if err != nil {
+		return
 	}
 
 	if t.AccessToken == "" {
-		err = fmt.Errorf("APIToken error: Unable to obtain Access Token thanks to API keys. Do you need to enable the API keys?")
+		err = fmt.Errorf("APIToken error: Unable to obtain Access Token thanks to API keys. Do you need to enable the API keys? %w", ErrUnauthorized)
 	}
 	return
 }
 
-// isExpired return false if the current token is valid. false otherwise.
-// An empty token is considered as invalid and will return expired = true.
-func (t *OAuthTokenResult) isExpired() (expired bool) {
-	expired = true
-	if t.AccessToken == "" {
+// ExpiresAt return the token expiration date.
+// It is computed from the token creation date (or the time it was received) and its lifetime.
+// An empty token returns a zero time.
+func (t *OAuthTokenResult) ExpiresAt() (expiresAt time.Time) {
+	if t == nil || t.AccessToken == "" {
 		return
 	}
-	expiredDate, err := time.Parse(time.RFC3339, t.CreatedAt)
-	if err != nil {
-		fmt.Printf("Error to interpret RFC3339 created date '%s'. Considering API token as expired\n", t.CreatedAt)
-		return
+	createdAt, err := time.Parse(time.RFC3339, t.CreatedAt)
+	if err != nil || (!t.obtainedAt.IsZero() && createdAt.After(t.obtainedAt)) {
+		// Unable to interpret the creation date or the server clock is ahead of ours.
+		createdAt = t.obtainedAt
+	}
+	return createdAt.Add(time.Second * time.Duration(t.ExpiresIn))
+}
+
+// Lifetime return the remaining time before the token expires.
+// It returns 0 if the token is empty or already expired.
+func (t *OAuthTokenResult) Lifetime() time.Duration {
+	expiresAt := t.ExpiresAt()
+	if expiresAt.IsZero() {
+		return 0
+	}
+	if lifetime := time.Until(expiresAt); lifetime > 0 {
+		return lifetime
+	}
+	return 0
+}
+
+// isExpired return false if the current token is valid. true otherwise.
+// An empty token is considered as invalid and will return expired = true.
+func (t *OAuthTokenResult) isExpired() (expired bool) {
+	return t.expiresWithin(0)
+}
+
+// expiresWithin return true if the token is empty, expired or will expire before the given margin.
+func (t *OAuthTokenResult) expiresWithin(margin time.Duration) bool {
+	if t == nil || t.AccessToken == "" {
+		return true
 	}
-	expiredDate.Add(time.Second * time.Duration(t.ExpiresIn))
-	return time.Now().After(expiredDate)
+	return t.Lifetime() <= margin
 }
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	// PasswordAlgorithmSaltSHA256 is the OneLogin algorithm of a password hashed with SaltedSHA256.
+	PasswordAlgorithmSaltSHA256 = "salt+sha256"
+
+	passwordMask = "********"
+)
+
+// Password is a secret string. It is sent as is to the API, but never printed by fmt or log functions.
+type Password string
+
+// String return a mask instead of the password.
+func (p Password) String() string {
+	return passwordMask
+}
+
+// GoString return a mask instead of the password.
+func (p Password) GoString() string {
+	return passwordMask
+}
+
+// Format print a mask instead of the password, whatever the format verb.
+func (p Password) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, passwordMask)
+}
+
+// SaltedSHA256 return the password hash as expected by OneLogin for the PasswordAlgorithmSaltSHA256 algorithm:
+// the hexadecimal SHA-256 of the salt followed by the password.
+func SaltedSHA256(password, salt string) Password {
+	hash := sha256.Sum256([]byte(salt + password))
+	return Password(hex.EncodeToString(hash[:]))
+}
+
+// NewPasswordSalt return a random salt of size bytes, hexadecimal encoded.
+func NewPasswordSalt(size int) (salt string, err error) {
+	buf := make([]byte, size)
+	if _, err = rand.Read(buf); err != nil {
+		return
+	}
+	return hex.EncodeToString(buf), nil
+}
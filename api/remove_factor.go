@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/multi-factor-authentication/remove-factor
+
+const (
+	// RemoveFactorURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/multi-factor-authentication/remove-factor
+	RemoveFactorURIPath = "api/1/users/%d/otp_devices/%d"
+)
+
+// RemoveFactorResult match the result of the end point requested
+type RemoveFactorResult struct {
+	Status ResultStatus
+}
+
+// NewRemoveFactor return a new object RemoveFactorResult
+func NewRemoveFactor() (ret *RemoveFactorResult) {
+	ret = new(RemoveFactorResult)
+	return
+}
+
+// Delete the request as defined by the API
+func (r *RemoveFactorResult) Delete(a *Core, userID int64, deviceID int) (response *http.Response, err error) {
+	return r.DeleteWithContext(context.Background(), a, userID, deviceID)
+}
+
+// DeleteWithContext is Delete cancelled when ctx is done.
+func (r *RemoveFactorResult) DeleteWithContext(ctx context.Context, a *Core, userID int64, deviceID int) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("RemoveFactorResult is nil")
+	}
+
+	response, err = a.requestRenewingToken(ctx, "DELETE", a.GetURL(RemoveFactorURIPath, userID, deviceID), nil, r)
+	return checkResponse(response, err, r.Status)
+}
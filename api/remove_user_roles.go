@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/remove-role-from-user
+
+const (
+	// RemoveUserRolesURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/remove-role-from-user
+	RemoveUserRolesURIPath = "api/1/users/%d/remove_roles"
+)
+
+// RemoveUserRolesResult match the result of the end point requested
+type RemoveUserRolesResult struct {
+	Status ResultStatus
+}
+
+// NewRemoveUserRoles return a new object RemoveUserRolesResult
+func NewRemoveUserRoles() (ret *RemoveUserRolesResult) {
+	ret = new(RemoveUserRolesResult)
+	return
+}
+
+// Put the request as defined by the API
+func (r *RemoveUserRolesResult) Put(a *Core, id int64, rolesID []int64) (response *http.Response, err error) {
+	return r.PutWithContext(context.Background(), a, id, rolesID)
+}
+
+// PutWithContext is Put cancelled when ctx is done.
+func (r *RemoveUserRolesResult) PutWithContext(ctx context.Context, a *Core, id int64, rolesID []int64) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("RemoveUserRolesResult is nil")
+	}
+
+	input := UserRolesRequest{RolesID: rolesID}
+
+	response, err = a.requestRenewingToken(ctx, "PUT", a.GetURL(RemoveUserRolesURIPath, id), input, r)
+	return checkResponse(response, err, r.Status)
+}
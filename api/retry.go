@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// See https://developers.onelogin.com/api-docs/1/getting-started/rate-limits
+const (
+	// RateLimitResetHeader gives the number of seconds before the rate limit is reset.
+	RateLimitResetHeader = "X-RateLimit-Reset"
+	// RateLimitRemainingHeader gives the number of requests left before the rate limit.
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+)
+
+// RetryPolicy define how API calls are retried when OneLogin is rate limiting (429), temporarily unavailable (5xx)
+// or when a transient network error occurs.
+//
+// Non idempotent calls (POST, like SAML assertion or verify factor) are never retried, unless RetryNonIdempotent is
+// true.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. 1 or less disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It is doubled after each attempt, up to MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay is the longest delay between 2 attempts. 0 is no limit.
+	MaxDelay time.Duration
+	// Jitter is the fraction (0 to 1) of the delay randomly removed, to avoid retry storms.
+	Jitter float64
+	// RetryNonIdempotent permits to retry POST calls.
+	RetryNonIdempotent bool
+}
+
+var (
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	jitterLock sync.Mutex
+)
+
+// NewRetryPolicy return a RetryPolicy with default values: 4 attempts, from 500ms up to 30s with 20% jitter.
+func NewRetryPolicy() (ret *RetryPolicy) {
+	ret = new(RetryPolicy)
+	ret.MaxAttempts = 4
+	ret.BaseDelay = 500 * time.Millisecond
+	ret.MaxDelay = 30 * time.Second
+	ret.Jitter = 0.2
+	return
+}
+
+// shouldRetry return true if the call can be retried after the given response or error.
+func (p *RetryPolicy) shouldRetry(idempotent bool, response *http.Response, err error) bool {
+	if p == nil {
+		return false
+	}
+	if !idempotent && !p.RetryNonIdempotent {
+		return false
+	}
+	if err != nil {
+		return isTransientError(err)
+	}
+	return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+}
+
+// delay return the time to wait before the next attempt, and false if the call must not be retried.
+// A rate limited response gives the time to wait with the X-RateLimit-Reset or Retry-After headers, at least
+// BaseDelay. If it is longer than MaxDelay, the call is not retried and the rate limited response is returned.
+func (p *RetryPolicy) delay(attempt int, response *http.Response) (time.Duration, bool) {
+	if response != nil && response.StatusCode == http.StatusTooManyRequests {
+		for _, header := range []string{RateLimitResetHeader, "Retry-After"} {
+			if seconds, err := strconv.Atoi(response.Header.Get(header)); err == nil && seconds >= 0 {
+				delay := time.Duration(seconds) * time.Second
+				if delay < p.BaseDelay {
+					delay = p.BaseDelay
+				}
+				if p.MaxDelay > 0 && delay > p.MaxDelay {
+					return 0, false
+				}
+				return delay, true
+			}
+		}
+	}
+
+	// MaxDelay 0 is no limit. The delay stops doubling before overflowing.
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay) && delay <= math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		jitterLock.Lock()
+		delay -= time.Duration(float64(delay) * p.Jitter * jitterRand.Float64())
+		jitterLock.Unlock()
+	}
+	return delay, true
+}
+
+// isTransientError return true for network errors which may succeed if retried.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// do execute the http call, retried as defined by the Core RetryPolicy.
+// A non idempotent call is retried only if the policy permits it.
+func (o *Core) do(ctx context.Context, method string, headers common.Headers, url string, input interface{}, idempotent bool) (response *http.Response, body []byte, err error) {
+	for attempt := 1; ; attempt++ {
+		response, body, err = common.Do(ctx, o.GetHTTPClient(), method, headers, url, input)
+
+		if attempt >= o.RetryPolicy.maxAttempts() || !o.RetryPolicy.shouldRetry(idempotent, response, err) {
+			return
+		}
+		delay, retry := o.RetryPolicy.delay(attempt, response)
+		if !retry {
+			return
+		}
+		if err = common.SleepWithContext(ctx, delay); err != nil {
+			return
+		}
+	}
+}
+
+// maxAttempts return the number of attempts allowed. 1 if there is no policy.
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil {
+		return 1
+	}
+	return p.MaxAttempts
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy return a fast RetryPolicy, without jitter.
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+}
+
+func rateLimited(header, value string) *http.Response {
+	response := &http.Response{StatusCode: http.StatusTooManyRequests, Header: make(http.Header)}
+	if header != "" {
+		response.Header.Set(header, value)
+	}
+	return response
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for _, test := range []struct {
+		attempt  int
+		response *http.Response
+		want     time.Duration
+		retry    bool
+	}{
+		{1, nil, time.Second, true},
+		{2, nil, 2 * time.Second, true},
+		{3, nil, 4 * time.Second, true},
+		{4, nil, 5 * time.Second, true},
+		{1, rateLimited(RateLimitResetHeader, "3"), 3 * time.Second, true},
+		{1, rateLimited("Retry-After", "2"), 2 * time.Second, true},
+		{1, rateLimited(RateLimitResetHeader, "0"), time.Second, true},
+		{1, rateLimited(RateLimitResetHeader, "60"), 0, false},
+		{3, rateLimited("", ""), 4 * time.Second, true},
+	} {
+		delay, retry := policy.delay(test.attempt, test.response)
+		if delay != test.want || retry != test.retry {
+			t.Errorf("delay(%d, %v) = %s, %t, want %s, %t", test.attempt, test.response, delay, retry, test.want,
+				test.retry)
+		}
+	}
+}
+
+func TestRetryPolicyDelayNoMaxDelay(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Second}
+	if delay, _ := policy.delay(4, nil); delay != 8*time.Second {
+		t.Errorf("delay = %s, want 8s", delay)
+	}
+	if delay, retry := policy.delay(1, rateLimited(RateLimitResetHeader, "3600")); delay != time.Hour || !retry {
+		t.Errorf("delay = %s, %t, want 1h, true", delay, retry)
+	}
+	if delay, _ := policy.delay(100, nil); delay <= 0 {
+		t.Errorf("delay = %s, want a positive delay", delay)
+	}
+}
+
+func TestRetryOnRateLimit(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			w.Header().Set(RateLimitResetHeader, "0")
+			writeStatus(w, http.StatusTooManyRequests, "Too Many Requests")
+			return
+		}
+		fmt.Fprint(w, `{"status":{"error":false,"code":200,"type":"success","message":"Success"},"data":[{"id":1,"name":"admin"}]}`)
+	})
+	defer stub.Close()
+	a := stub.core()
+	a.SetRetryPolicy(testRetryPolicy())
+
+	if _, err := NewGetRoleByID().Get(a, 1); err != nil {
+		t.Fatalf("Get: %s", err)
+	}
+	if got := stub.count(fmt.Sprintf(GetRoleByIDURIPath, 1)); got != 2 {
+		t.Errorf("role requests = %d, want 2", got)
+	}
+}
+
+func TestRateLimitLongerThanMaxDelay(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Header().Set(RateLimitResetHeader, "60")
+		writeStatus(w, http.StatusTooManyRequests, "Too Many Requests")
+	})
+	defer stub.Close()
+	a := stub.core()
+	a.SetRetryPolicy(testRetryPolicy())
+
+	_, err := NewGetRoleByID().Get(a, 1)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, want ErrRateLimited", err)
+	}
+	if got := stub.count(fmt.Sprintf(GetRoleByIDURIPath, 1)); got != 1 {
+		t.Errorf("role requests = %d, want 1", got)
+	}
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		writeStatus(w, http.StatusServiceUnavailable, "Service Unavailable")
+	})
+	defer stub.Close()
+	a := stub.core()
+	a.SetRetryPolicy(testRetryPolicy())
+
+	if _, err := NewGetRoleByID().Get(a, 1); err == nil {
+		t.Fatal("Get succeeded, want an error")
+	}
+	if got := stub.count(fmt.Sprintf(GetRoleByIDURIPath, 1)); got != 3 {
+		t.Errorf("role requests = %d, want 3", got)
+	}
+}
+
+func TestNonIdempotentNotRetried(t *testing.T) {
+	stub := newStubOneLogin(func(w http.ResponseWriter, r *http.Request, call int) {
+		writeStatus(w, http.StatusServiceUnavailable, "Service Unavailable")
+	})
+	defer stub.Close()
+	a := stub.core()
+	a.SetRetryPolicy(testRetryPolicy())
+
+	if _, err := NewSAMLAssertionResult().Post(a, "user", "password", "1", "test", ""); err == nil {
+		t.Fatal("Post succeeded, want an error")
+	}
+	if got := stub.count(SAMLAssertionURIPath); got != 1 {
+		t.Errorf("saml_assertion posted %d times, want 1", got)
+	}
+}
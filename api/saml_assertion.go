@@ -1,10 +1,10 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
-	"github.com/clarsonneur/onelogin/common"
 )
 
 const (
@@ -37,6 +37,11 @@ func NewSAMLAssertionResult() (ret *SAMLAssertionResult) {
 
 // Post the SAMLAssertion request and saved it to the SAMLAssertionResult
 func (r *SAMLAssertionResult) Post(a *Core, user, pass, appID, subDomain, IP string) (response *http.Response, err error) {
+	return r.PostWithContext(context.Background(), a, user, pass, appID, subDomain, IP)
+}
+
+// PostWithContext is Post cancelled when ctx is done.
+func (r *SAMLAssertionResult) PostWithContext(ctx context.Context, a *Core, user, pass, appID, subDomain, IP string) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("SAMLAssertionResult is nil")
 	}
@@ -49,6 +54,6 @@ func (r *SAMLAssertionResult) Post(a *Core, user, pass, appID, subDomain, IP str
 		IPAddress: IP,
 	}
 
-	response, err = common.Request("POST", a.getBearerHeaders(), a.GetURL(SAMLAssertionURIPath), input, r)
+	response, err = a.request(ctx, "POST", a.GetURL(SAMLAssertionURIPath), input, r)
 	return checkResponse(response, err, r.Status)
 }
@@ -1,10 +1,9 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
-
-	"github.com/clarsonneur/onelogin/common"
 )
 
 // https://developers.onelogin.com/api-docs/1/users/set-custom-attribute
@@ -33,10 +32,15 @@ func NewPutCustomAttrs() (ret *PutUserAttrsResult) {
 
 // Put the request as defined by the API
 func (r *PutUserAttrsResult) Put(a *Core, id int64, input PutUserAttrsRequest) (response *http.Response, err error) {
+	return r.PutWithContext(context.Background(), a, id, input)
+}
+
+// PutWithContext is Put cancelled when ctx is done.
+func (r *PutUserAttrsResult) PutWithContext(ctx context.Context, a *Core, id int64, input PutUserAttrsRequest) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("PutUserAttrsResult is nil")
 	}
 
-	response, err = common.Request("PUT", a.getBearerHeaders(), a.GetURL(SetCustomAttrsIDURIPath, id), input, r)
+	response, err = a.requestRenewingToken(ctx, "PUT", a.GetURL(SetCustomAttrsIDURIPath, id), input, r)
 	return checkResponse(response, err, r.Status)
 }
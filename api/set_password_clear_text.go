@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/set-password-in-cleartext
+
+const (
+	// SetPasswordClearTextURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/set-password-in-cleartext
+	SetPasswordClearTextURIPath = "api/1/users/set_password_clear_text/%d"
+)
+
+// SetPasswordClearTextResult match the result of the end point requested
+type SetPasswordClearTextResult struct {
+	Status ResultStatus
+}
+
+// SetPasswordClearTextRequest is the input request structure for this API call.
+type SetPasswordClearTextRequest struct {
+	Password             Password `json:"password"`
+	PasswordConfirmation Password `json:"password_confirmation"`
+	// ValidatePolicy checks the password against the user password policy.
+	ValidatePolicy bool `json:"validate_policy"`
+}
+
+// NewSetPasswordClearText return a new object SetPasswordClearTextResult
+func NewSetPasswordClearText() (ret *SetPasswordClearTextResult) {
+	ret = new(SetPasswordClearTextResult)
+	return
+}
+
+// Put the request as defined by the API
+func (r *SetPasswordClearTextResult) Put(a *Core, id int64, password Password, validatePolicy bool) (response *http.Response, err error) {
+	return r.PutWithContext(context.Background(), a, id, password, validatePolicy)
+}
+
+// PutWithContext is Put cancelled when ctx is done.
+func (r *SetPasswordClearTextResult) PutWithContext(ctx context.Context, a *Core, id int64, password Password, validatePolicy bool) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("SetPasswordClearTextResult is nil")
+	}
+
+	input := SetPasswordClearTextRequest{
+		Password:             password,
+		PasswordConfirmation: password,
+		ValidatePolicy:       validatePolicy,
+	}
+
+	response, err = a.requestRenewingToken(ctx, "PUT", a.GetURL(SetPasswordClearTextURIPath, id), input, r)
+	return checkResponse(response, err, r.Status)
+}
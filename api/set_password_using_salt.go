@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/users/set-password-using-sha-256
+
+const (
+	// SetPasswordUsingSaltURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/users/set-password-using-sha-256
+	SetPasswordUsingSaltURIPath = "api/1/users/set_password_using_salt/%d"
+)
+
+// SetPasswordUsingSaltResult match the result of the end point requested
+type SetPasswordUsingSaltResult struct {
+	Status ResultStatus
+}
+
+// SetPasswordUsingSaltRequest is the input request structure for this API call.
+type SetPasswordUsingSaltRequest struct {
+	Password             Password `json:"password"`
+	PasswordConfirmation Password `json:"password_confirmation"`
+	PasswordAlgorithm    string   `json:"password_algorithm"`
+	PasswordSalt         string   `json:"password_salt,omitempty"`
+}
+
+// NewSetPasswordUsingSalt return a new object SetPasswordUsingSaltResult
+func NewSetPasswordUsingSalt() (ret *SetPasswordUsingSaltResult) {
+	ret = new(SetPasswordUsingSaltResult)
+	return
+}
+
+// Put the request as defined by the API
+// hash is the password hashed with the salt, as returned by SaltedSHA256.
+func (r *SetPasswordUsingSaltResult) Put(a *Core, id int64, hash Password, salt string) (response *http.Response, err error) {
+	return r.PutWithContext(context.Background(), a, id, hash, salt)
+}
+
+// PutWithContext is Put cancelled when ctx is done.
+func (r *SetPasswordUsingSaltResult) PutWithContext(ctx context.Context, a *Core, id int64, hash Password, salt string) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("SetPasswordUsingSaltResult is nil")
+	}
+
+	input := SetPasswordUsingSaltRequest{
+		Password:             hash,
+		PasswordConfirmation: hash,
+		PasswordAlgorithm:    PasswordAlgorithmSaltSHA256,
+		PasswordSalt:         salt,
+	}
+
+	response, err = a.requestRenewingToken(ctx, "PUT", a.GetURL(SetPasswordUsingSaltURIPath, id), input, r)
+	return checkResponse(response, err, r.Status)
+}
@@ -1,10 +1,10 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
-
-	"github.com/clarsonneur/onelogin/common"
 )
 
 // https://developers.onelogin.com/api-docs/1/users/update-user
@@ -22,8 +22,185 @@ type PutUserByIDResult struct {
 }
 
 // PutUserRequest is the input request structure for this API call.
+//
+// Only fields set are sent to OneLogin, so that other user fields are kept unchanged.
+// Set a string field to "" to empty it, or use Clear to set a field to null.
 type PutUserRequest struct {
-	CustomAttrs map[string]string `json:"custom_attributes,omitempty"`
+	Username      *string           `json:"username,omitempty"`
+	Email         *string           `json:"email,omitempty"`
+	Firstname     *string           `json:"firstname,omitempty"`
+	Lastname      *string           `json:"lastname,omitempty"`
+	Department    *string           `json:"department,omitempty"`
+	Title         *string           `json:"title,omitempty"`
+	Company       *string           `json:"company,omitempty"`
+	Phone         *string           `json:"phone,omitempty"`
+	Comment       *string           `json:"comment,omitempty"`
+	ManagerUserID *int64            `json:"manager_user_id,omitempty"`
+	GroupID       *int64            `json:"group_id,omitempty"`
+	Status        *int              `json:"status,omitempty"`
+	State         *int              `json:"state,omitempty"`
+	CustomAttrs   map[string]string `json:"custom_attributes,omitempty"`
+
+	// clear is the list of json fields to set to null
+	clear []string
+}
+
+// NewPutUserRequest return an empty PutUserRequest. Nothing is updated until a field is set.
+func NewPutUserRequest() (ret *PutUserRequest) {
+	ret = new(PutUserRequest)
+	return
+}
+
+// NewPutUserRequestFromDiff return a PutUserRequest updating only the standard fields which differ between before
+// and after.
+// Custom attributes are not compared. Use PutUserAttrsResult to update them.
+func NewPutUserRequestFromDiff(before, after User) (ret *PutUserRequest) {
+	ret = NewPutUserRequest()
+	diffString := func(field **string, before, after string) {
+		if before != after {
+			*field = &after
+		}
+	}
+	diffString(&ret.Username, before.Username, after.Username)
+	diffString(&ret.Email, before.Email, after.Email)
+	diffString(&ret.Firstname, before.Firstname, after.Firstname)
+	diffString(&ret.Lastname, before.Lastname, after.Lastname)
+	diffString(&ret.Department, before.Department, after.Department)
+	diffString(&ret.Title, before.Title, after.Title)
+	diffString(&ret.Company, before.Company, after.Company)
+	diffString(&ret.Phone, before.Phone, after.Phone)
+	diffString(&ret.Comment, before.Comment, after.Comment)
+	if before.ManagerUserID != after.ManagerUserID {
+		if after.ManagerUserID == 0 {
+			ret.Clear("manager_user_id")
+		} else {
+			ret.SetManagerUserID(after.ManagerUserID)
+		}
+	}
+	if before.GroupID != after.GroupID {
+		if after.GroupID == 0 {
+			ret.Clear("group_id")
+		} else {
+			ret.SetGroupID(after.GroupID)
+		}
+	}
+	if before.Status != after.Status {
+		ret.SetStatus(after.Status)
+	}
+	if before.State != after.State {
+		ret.SetState(after.State)
+	}
+	return
+}
+
+// SetUsername update the user name
+func (r *PutUserRequest) SetUsername(value string) *PutUserRequest {
+	r.Username = &value
+	return r
+}
+
+// SetEmail update the user email
+func (r *PutUserRequest) SetEmail(value string) *PutUserRequest {
+	r.Email = &value
+	return r
+}
+
+// SetFirstname update the user first name
+func (r *PutUserRequest) SetFirstname(value string) *PutUserRequest {
+	r.Firstname = &value
+	return r
+}
+
+// SetLastname update the user last name
+func (r *PutUserRequest) SetLastname(value string) *PutUserRequest {
+	r.Lastname = &value
+	return r
+}
+
+// SetDepartment update the user department
+func (r *PutUserRequest) SetDepartment(value string) *PutUserRequest {
+	r.Department = &value
+	return r
+}
+
+// SetTitle update the user title
+func (r *PutUserRequest) SetTitle(value string) *PutUserRequest {
+	r.Title = &value
+	return r
+}
+
+// SetCompany update the user company
+func (r *PutUserRequest) SetCompany(value string) *PutUserRequest {
+	r.Company = &value
+	return r
+}
+
+// SetPhone update the user phone number
+func (r *PutUserRequest) SetPhone(value string) *PutUserRequest {
+	r.Phone = &value
+	return r
+}
+
+// SetComment update the user comment
+func (r *PutUserRequest) SetComment(value string) *PutUserRequest {
+	r.Comment = &value
+	return r
+}
+
+// SetManagerUserID update the user manager
+func (r *PutUserRequest) SetManagerUserID(value int64) *PutUserRequest {
+	r.ManagerUserID = &value
+	return r
+}
+
+// SetGroupID assign the user to a group
+func (r *PutUserRequest) SetGroupID(value int64) *PutUserRequest {
+	r.GroupID = &value
+	return r
+}
+
+// SetStatus update the user status (StatusActive, StatusSuspended, ...)
+func (r *PutUserRequest) SetStatus(value int) *PutUserRequest {
+	r.Status = &value
+	return r
+}
+
+// SetState update the user state (StateApproved, StateRejected, ...)
+func (r *PutUserRequest) SetState(value int) *PutUserRequest {
+	r.State = &value
+	return r
+}
+
+// Clear set the given json fields to null. Ex: Clear("manager_user_id", "phone")
+func (r *PutUserRequest) Clear(fields ...string) *PutUserRequest {
+	r.clear = append(r.clear, fields...)
+	return r
+}
+
+// IsEmpty return true if the request does not update anything.
+func (r PutUserRequest) IsEmpty() bool {
+	data, err := r.MarshalJSON()
+	return err == nil && string(data) == "{}"
+}
+
+// MarshalJSON encode only the fields set, and fields cleared as null.
+func (r PutUserRequest) MarshalJSON() ([]byte, error) {
+	// putUserFields has the same fields without the MarshalJSON method.
+	type putUserFields PutUserRequest
+
+	data, err := json.Marshal(putUserFields(r))
+	if err != nil || len(r.clear) == 0 {
+		return data, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range r.clear {
+		fields[field] = json.RawMessage("null")
+	}
+	return json.Marshal(fields)
 }
 
 // NewPutUserByID return a new object PutUserByIDResult
@@ -34,10 +211,15 @@ func NewPutUserByID() (ret *PutUserByIDResult) {
 
 // Put the request as defined by the API
 func (r *PutUserByIDResult) Put(a *Core, id int64, input PutUserRequest) (response *http.Response, err error) {
+	return r.PutWithContext(context.Background(), a, id, input)
+}
+
+// PutWithContext is Put cancelled when ctx is done.
+func (r *PutUserByIDResult) PutWithContext(ctx context.Context, a *Core, id int64, input PutUserRequest) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("PutUserByIDResult is nil")
 	}
 
-	response, err = common.Request("PUT", a.getBearerHeaders(), a.GetURL(UpdateUserByIDURIPath, id), input, r)
+	response, err = a.requestRenewingToken(ctx, "PUT", a.GetURL(UpdateUserByIDURIPath, id), input, r)
 	return checkResponse(response, err, r.Status)
 }
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPutUserRequestMarshalJSON(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		request *PutUserRequest
+		want    string
+	}{
+		{"empty", NewPutUserRequest(), `{}`},
+		{"set", NewPutUserRequest().SetFirstname("John").SetGroupID(12), `{"firstname":"John","group_id":12}`},
+		{"emptied", NewPutUserRequest().SetPhone(""), `{"phone":""}`},
+		{"cleared", NewPutUserRequest().Clear("manager_user_id", "phone"), `{"manager_user_id":null,"phone":null}`},
+		{"set and cleared", NewPutUserRequest().SetTitle("CTO").Clear("group_id"), `{"group_id":null,"title":"CTO"}`},
+		{"clear wins", NewPutUserRequest().SetPhone("0123").Clear("phone"), `{"phone":null}`},
+	} {
+		data, err := json.Marshal(test.request)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if string(data) != test.want {
+			t.Errorf("%s: json = %s, want %s", test.name, data, test.want)
+		}
+	}
+}
+
+func TestPutUserRequestIsEmpty(t *testing.T) {
+	if !NewPutUserRequest().IsEmpty() {
+		t.Error("new request is not empty")
+	}
+	if NewPutUserRequest().Clear("phone").IsEmpty() {
+		t.Error("request clearing a field is empty")
+	}
+}
+
+func TestNewPutUserRequestFromDiff(t *testing.T) {
+	before := User{Firstname: "John", Phone: "0123", ManagerUserID: 3, GroupID: 4, Status: 1}
+	after := before
+	after.Phone = ""
+	after.ManagerUserID = 0
+	after.GroupID = 5
+
+	data, err := json.Marshal(NewPutUserRequestFromDiff(before, after))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"group_id":5,"manager_user_id":null,"phone":""}`; string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+	if !NewPutUserRequestFromDiff(before, before).IsEmpty() {
+		t.Error("request without differences is not empty")
+	}
+}
@@ -30,9 +30,14 @@ type User struct {
 	RolesID       []int64           `json:"role_id"`
 	ManagerUserID int64             `json:"manager_user_id"`
 	MemberOf      string            `json:"member_of"`
+	GroupID       int64             `json:"group_id"`
 	Firstname     string            `json:"firstname"`
 	Lastname      string            `json:"lastname"`
 	Department    string            `json:"department"`
+	Title         string            `json:"title"`
+	Company       string            `json:"company"`
+	Phone         string            `json:"phone"`
+	Comment       string            `json:"comment"`
 	CustomAttrs   map[string]string `json:"custom_attributes"`
 }
 
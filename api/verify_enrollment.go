@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// https://developers.onelogin.com/api-docs/1/multi-factor-authentication/verify-factor
+
+const (
+	// VerifyEnrollmentURIPath defined the API Path for such request.
+	// As defined by https://developers.onelogin.com/api-docs/1/multi-factor-authentication/verify-factor
+	VerifyEnrollmentURIPath = "api/1/users/%d/otp_devices/%d/verify"
+)
+
+// VerifyEnrollmentResult match the result of the end point requested
+type VerifyEnrollmentResult struct {
+	Status ResultStatus
+}
+
+// VerifyEnrollmentRequest is the input request structure for this API call.
+type VerifyEnrollmentRequest struct {
+	OTPToken   string `json:"otp_token,omitempty"`
+	StateToken string `json:"state_token,omitempty"`
+}
+
+// NewVerifyEnrollment return a new object VerifyEnrollmentResult
+func NewVerifyEnrollment() (ret *VerifyEnrollmentResult) {
+	ret = new(VerifyEnrollmentResult)
+	return
+}
+
+// Post the request as defined by the API
+// stateToken is given by the device enrollment or activation.
+func (r *VerifyEnrollmentResult) Post(a *Core, userID int64, deviceID int, OTPToken, stateToken string) (response *http.Response, err error) {
+	return r.PostWithContext(context.Background(), a, userID, deviceID, OTPToken, stateToken)
+}
+
+// PostWithContext is Post cancelled when ctx is done.
+func (r *VerifyEnrollmentResult) PostWithContext(ctx context.Context, a *Core, userID int64, deviceID int, OTPToken, stateToken string) (response *http.Response, err error) {
+	if r == nil {
+		return nil, errors.New("VerifyEnrollmentResult is nil")
+	}
+
+	input := VerifyEnrollmentRequest{
+		OTPToken:   OTPToken,
+		StateToken: stateToken,
+	}
+
+	response, err = a.request(ctx, "POST", a.GetURL(VerifyEnrollmentURIPath, userID, deviceID), input, r)
+	return checkResponse(response, err, r.Status)
+}
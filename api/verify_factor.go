@@ -1,8 +1,8 @@
 package api
 
 import (
+	"context"
 	"errors"
-	"github.com/clarsonneur/onelogin/common"
 	"net/http"
 	"strconv"
 )
@@ -38,6 +38,13 @@ func NewVerifyFactorResult() (ret *VerifyFactorResult) {
 
 // Post the request as defined by the API
 func (r *VerifyFactorResult) Post(a *Core, appID string, deviceID int, stateToken, OTPToken string, doNotNotify bool) (response *http.Response, err error) {
+	return r.PostWithContext(context.Background(), a, appID, deviceID, stateToken, OTPToken, doNotNotify)
+}
+
+// PostWithContext is Post cancelled when ctx is done.
+// A push notification poll (no OTPToken and doNotNotify) sends nothing to verify, so the token is renewed and the
+// poll retried once if the API rejects it (401). Then a 401 is the answer of OneLogin: the notification is denied.
+func (r *VerifyFactorResult) PostWithContext(ctx context.Context, a *Core, appID string, deviceID int, stateToken, OTPToken string, doNotNotify bool) (response *http.Response, err error) {
 	if r == nil {
 		return nil, errors.New("VerifyFactorResult is nil")
 	}
@@ -50,6 +57,10 @@ func (r *VerifyFactorResult) Post(a *Core, appID string, deviceID int, stateToke
 		DoNotNotify: doNotNotify,
 	}
 
-	response, err = common.Request("POST", a.getBearerHeaders(), a.GetURL(VerifyFactorURIPath), input, r)
+	if OTPToken == "" && doNotNotify {
+		response, err = a.requestRenewingToken(ctx, "POST", a.GetURL(VerifyFactorURIPath), input, r)
+	} else {
+		response, err = a.request(ctx, "POST", a.GetURL(VerifyFactorURIPath), input, r)
+	}
 	return checkResponse(response, err, r.Status)
 }
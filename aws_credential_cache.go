@@ -0,0 +1,135 @@
+package onelogin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// DefaultCredentialRefreshMargin is the time before expiration when cached AWS credentials are renewed.
+const DefaultCredentialRefreshMargin = 5 * time.Minute
+
+// AwsCredentialCacheKey identifies cached AWS credentials.
+type AwsCredentialCacheKey struct {
+	Subdomain string
+	Username  string
+	AppID     string
+	// Role is the role requested. See AwsRoleSelector.
+	Role string
+}
+
+// String return the key as "subdomain/username/appID/role"
+func (k AwsCredentialCacheKey) String() string {
+	return strings.Join([]string{k.Subdomain, k.Username, k.AppID, k.Role}, "/")
+}
+
+// AwsCredentialCache stores AWS credentials obtained with a SAML assertion on disk, so a new MFA challenge is not
+// required until they expire.
+// Each entry is a 0600 file, in a 0700 directory.
+type AwsCredentialCache struct {
+	// Dir is the cache directory.
+	Dir string
+	// RefreshMargin is the time before expiration when cached credentials are not served anymore.
+	RefreshMargin time.Duration
+}
+
+// awsCredentialCacheEntry is the content of a cache file.
+type awsCredentialCacheEntry struct {
+	Key         AwsCredentialCacheKey
+	Credentials *AwsCredentials
+}
+
+// DefaultAwsCredentialCacheDir return the default cache directory: ~/.ol-aws/cache
+func DefaultAwsCredentialCacheDir() string {
+	return filepath.Join(common.UserHomeDir(), ".ol-aws", "cache")
+}
+
+// NewAwsCredentialCache creates an AwsCredentialCache with the DefaultCredentialRefreshMargin.
+// An empty dir is DefaultAwsCredentialCacheDir().
+func NewAwsCredentialCache(dir string) (ret *AwsCredentialCache) {
+	ret = new(AwsCredentialCache)
+	ret.Dir = dir
+	if ret.Dir == "" {
+		ret.Dir = DefaultAwsCredentialCacheDir()
+	}
+	ret.RefreshMargin = DefaultCredentialRefreshMargin
+	return
+}
+
+// Get return the credentials cached for the key, if they are still valid for the refresh margin. nil otherwise.
+func (c *AwsCredentialCache) Get(key AwsCredentialCacheKey) *AwsCredentials {
+	if c == nil {
+		return nil
+	}
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	entry := new(awsCredentialCacheEntry)
+	if err = json.Unmarshal(data, entry); err != nil {
+		logger.Warningf("Ignoring invalid cached credentials %s: %s", c.path(key), err)
+		return nil
+	}
+	creds := entry.Credentials
+	if entry.Key != key || creds == nil {
+		return nil
+	}
+	if creds.Expiration.IsZero() || time.Until(creds.Expiration) <= c.RefreshMargin {
+		return nil
+	}
+	return creds
+}
+
+// Put stores the credentials for the key.
+func (c *AwsCredentialCache) Put(key AwsCredentialCacheKey, creds *AwsCredentials) (err error) {
+	if c == nil {
+		return errors.New("AwsCredentialCache is nil")
+	}
+	data, err := json.Marshal(awsCredentialCacheEntry{Key: key, Credentials: creds})
+	if err != nil {
+		return
+	}
+	return common.WriteFileAtomic(c.path(key), data, 0600)
+}
+
+// Invalidate removes the credentials cached for the key, if any.
+func (c *AwsCredentialCache) Invalidate(key AwsCredentialCacheKey) (err error) {
+	if c == nil {
+		return errors.New("AwsCredentialCache is nil")
+	}
+	if err = os.Remove(c.path(key)); os.IsNotExist(err) {
+		return nil
+	}
+	return
+}
+
+// Clear removes all cached credentials.
+func (c *AwsCredentialCache) Clear() (err error) {
+	if c == nil {
+		return errors.New("AwsCredentialCache is nil")
+	}
+	files, err := filepath.Glob(filepath.Join(c.Dir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		if err = os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return
+		}
+	}
+	return nil
+}
+
+// path return the cache file of the key.
+func (c *AwsCredentialCache) path(key AwsCredentialCacheKey) string {
+	sum := sha256.Sum256([]byte(key.String()))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
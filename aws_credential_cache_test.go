@@ -0,0 +1,122 @@
+package onelogin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCache creates a cache in a new temporary directory. Remove the directory when done.
+func newTestCache(t *testing.T) (cache *AwsCredentialCache, dir string) {
+	dir, err := ioutil.TempDir("", "aws-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAwsCredentialCache(filepath.Join(dir, "cache")), dir
+}
+
+func testCredentials(validity time.Duration) *AwsCredentials {
+	return &AwsCredentials{AccessKeyID: "ASIAEXAMPLE", SecretAccessKey: "secret", SessionToken: "token",
+		Expiration: time.Now().Add(validity).UTC().Round(time.Second), RoleARN: testAwsRole.RoleARN}
+}
+
+var testCacheKey = AwsCredentialCacheKey{Subdomain: "myCompany", Username: "me", AppID: "123", Role: "prod/Admin"}
+
+func TestAwsCredentialCachePermissions(t *testing.T) {
+	cache, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	if err := cache.Put(testCacheKey, testCredentials(time.Hour)); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+	info, err := os.Stat(cache.path(testCacheKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("file mode = %o, want 600", mode)
+	}
+	if info, err = os.Stat(cache.Dir); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("directory mode = %v, %v, want 700", info.Mode().Perm(), err)
+	}
+}
+
+func TestAwsCredentialCacheKeys(t *testing.T) {
+	cache, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	creds := testCredentials(time.Hour)
+	if err := cache.Put(testCacheKey, creds); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+	if got := cache.Get(testCacheKey); got == nil || *got != *creds {
+		t.Errorf("Get = %+v, want %+v", got, creds)
+	}
+
+	for _, key := range []AwsCredentialCacheKey{
+		{Subdomain: "other", Username: "me", AppID: "123", Role: "prod/Admin"},
+		{Subdomain: "myCompany", Username: "you", AppID: "123", Role: "prod/Admin"},
+		{Subdomain: "myCompany", Username: "me", AppID: "456", Role: "prod/Admin"},
+		{Subdomain: "myCompany", Username: "me", AppID: "123", Role: "prod/ReadOnly"},
+	} {
+		if got := cache.Get(key); got != nil {
+			t.Errorf("Get(%s) = %+v, want nil", key, got)
+		}
+	}
+}
+
+func TestAwsCredentialCacheRefreshMargin(t *testing.T) {
+	cache, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	if err := cache.Put(testCacheKey, testCredentials(10*time.Minute)); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+	if cache.Get(testCacheKey) == nil {
+		t.Error("credentials valid for 10 minutes not served with a 5 minutes margin")
+	}
+	cache.RefreshMargin = 15 * time.Minute
+	if cache.Get(testCacheKey) != nil {
+		t.Error("credentials valid for 10 minutes served with a 15 minutes margin")
+	}
+
+	cache.RefreshMargin = 0
+	if err := cache.Put(testCacheKey, testCredentials(-time.Minute)); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+	if cache.Get(testCacheKey) != nil {
+		t.Error("expired credentials served")
+	}
+}
+
+func TestAwsCredentialCacheInvalidate(t *testing.T) {
+	cache, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	other := testCacheKey
+	other.Role = "prod/ReadOnly"
+	for _, key := range []AwsCredentialCacheKey{testCacheKey, other} {
+		if err := cache.Put(key, testCredentials(time.Hour)); err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+	}
+
+	if err := cache.Invalidate(testCacheKey); err != nil {
+		t.Fatalf("Invalidate: %s", err)
+	}
+	if cache.Get(testCacheKey) != nil || cache.Get(other) == nil {
+		t.Error("Invalidate did not remove only the key given")
+	}
+	if err := cache.Invalidate(testCacheKey); err != nil {
+		t.Errorf("Invalidate of a missing key: %s", err)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear: %s", err)
+	}
+	if cache.Get(other) != nil {
+		t.Error("Clear did not remove all credentials")
+	}
+}
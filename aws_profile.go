@@ -0,0 +1,98 @@
+package onelogin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// AwsProfileWriter writes AWS credentials and configuration profiles, as read by AWS CLI and SDKs.
+// Other profiles and comments of the files are preserved.
+type AwsProfileWriter struct {
+	// CredentialsFile is the AWS shared credentials file. Default is ~/.aws/credentials or $AWS_SHARED_CREDENTIALS_FILE
+	CredentialsFile string
+	// ConfigFile is the AWS config file. Default is ~/.aws/config or $AWS_CONFIG_FILE
+	ConfigFile string
+}
+
+// NewAwsProfileWriter creates an AwsProfileWriter on the default AWS files.
+func NewAwsProfileWriter() (ret *AwsProfileWriter) {
+	ret = new(AwsProfileWriter)
+	ret.CredentialsFile = os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if ret.CredentialsFile == "" {
+		ret.CredentialsFile = filepath.Join(common.DefaultAWSProfilePath(), "credentials")
+	}
+	ret.ConfigFile = os.Getenv("AWS_CONFIG_FILE")
+	if ret.ConfigFile == "" {
+		ret.ConfigFile = filepath.Join(common.DefaultAWSProfilePath(), "config")
+	}
+	return
+}
+
+// Write upserts the profile credentials in the credentials file, and the region and output in the config file.
+// Empty region and output are not written. Files are replaced atomically, with 0600 permissions.
+func (w *AwsProfileWriter) Write(profile string, credentials *AwsCredentials, region, output string) (err error) {
+	if w == nil {
+		return errors.New("AwsProfileWriter is nil")
+	}
+	if profile == "" {
+		return errors.New("AWS profile name is empty")
+	}
+	if credentials == nil {
+		return errors.New("AWS credentials are nil")
+	}
+
+	if err = w.writeCredentials(profile, credentials); err != nil {
+		return
+	}
+	if region == "" && output == "" {
+		return
+	}
+	return w.writeConfig(profile, region, output)
+}
+
+// writeCredentials writes the standard AWS credentials keys only. Keys left by previous credentials of the profile
+// (session token, non standard aws_session_expiration) are removed.
+func (w *AwsProfileWriter) writeCredentials(profile string, credentials *AwsCredentials) (err error) {
+	file, err := common.ReadIniFile(w.CredentialsFile)
+	if err != nil {
+		return
+	}
+	values := map[string]string{
+		"aws_access_key_id":     credentials.AccessKeyID,
+		"aws_secret_access_key": credentials.SecretAccessKey,
+	}
+	obsolete := []string{"aws_session_expiration"}
+	if credentials.SessionToken != "" {
+		values["aws_session_token"] = credentials.SessionToken
+	} else {
+		obsolete = append(obsolete, "aws_session_token")
+	}
+	file.Set(profile, values)
+	file.Delete(profile, obsolete...)
+	return file.Write(w.CredentialsFile, 0600)
+}
+
+func (w *AwsProfileWriter) writeConfig(profile, region, output string) (err error) {
+	file, err := common.ReadIniFile(w.ConfigFile)
+	if err != nil {
+		return
+	}
+
+	// Except the default one, profiles are prefixed by "profile" in the config file.
+	section := profile
+	if profile != "default" {
+		section = "profile " + profile
+	}
+	values := make(map[string]string)
+	if region != "" {
+		values["region"] = region
+	}
+	if output != "" {
+		values["output"] = output
+	}
+	file.Set(section, values)
+	return file.Write(w.ConfigFile, 0600)
+}
@@ -0,0 +1,50 @@
+package onelogin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAwsProfileWriterWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aws-profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w := &AwsProfileWriter{
+		CredentialsFile: filepath.Join(dir, "credentials"),
+		ConfigFile:      filepath.Join(dir, "config"),
+	}
+	old := "[prod]\n# managed by ol-aws\naws_access_key_id = OLD\naws_secret_access_key = old\n" +
+		"aws_session_token = old-token\naws_session_expiration = 2020-01-02T10:00:00Z\n"
+	if err = ioutil.WriteFile(w.CredentialsFile, []byte(old), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	creds := &AwsCredentials{AccessKeyID: "NEW", SecretAccessKey: "new", SessionToken: "new-token",
+		Expiration: time.Now().Add(time.Hour)}
+	if err = w.Write("prod", creds, "eu-west-1", ""); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	want := "[prod]\n# managed by ol-aws\naws_access_key_id = NEW\naws_secret_access_key = new\n" +
+		"aws_session_token = new-token\n"
+	if data, _ := ioutil.ReadFile(w.CredentialsFile); string(data) != want {
+		t.Errorf("credentials =\n%s\nwant\n%s", data, want)
+	}
+	if data, _ := ioutil.ReadFile(w.ConfigFile); string(data) != "[profile prod]\nregion = eu-west-1\n" {
+		t.Errorf("config =\n%s", data)
+	}
+
+	// Long term credentials, without session token.
+	if err = w.Write("prod", &AwsCredentials{AccessKeyID: "KEY", SecretAccessKey: "secret"}, "", ""); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	want = "[prod]\n# managed by ol-aws\naws_access_key_id = KEY\naws_secret_access_key = secret\n"
+	if data, _ := ioutil.ReadFile(w.CredentialsFile); string(data) != want {
+		t.Errorf("credentials =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -3,6 +3,7 @@ package onelogin
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
 )
 
 // AwsSAMLAssertion provide information back to the caller.
@@ -12,7 +13,9 @@ type AwsSAMLAssertion struct {
 	MfaVerifyInfo       struct {
 		DeviceID   int
 		DeviceType string
-		OTPToken   int
+		OTPToken   string
+		// PushResult is PushApproved if the authentication was approved with a push notification.
+		PushResult PushResult
 	}
 	User        string
 	Password    string
@@ -33,6 +36,8 @@ func (a *AwsSAMLAssertion) SetDecoded(data []byte) (err error) {
 		return fmt.Errorf("SetDecode: AwsSAMLAssertion object is nil")
 	}
 	a.EncodedSamlResponse = data
-	a.SamlResponse, err = base64.RawStdEncoding.DecodeString(string(data))
+	// OneLogin may return the response padded and wrapped. Decode it without padding and new lines.
+	encoded := strings.NewReplacer("\n", "", "\r", "").Replace(string(data))
+	a.SamlResponse, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(encoded, "="))
 	return
 }
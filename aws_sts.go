@@ -0,0 +1,200 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSTSEndpoint is the AWS STS global endpoint.
+const DefaultSTSEndpoint = "https://sts.amazonaws.com/"
+
+// AwsCredentials are the temporary AWS credentials returned by STS.
+type AwsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+	// RoleARN is the role assumed.
+	RoleARN string
+}
+
+// STSClient calls the AWS STS AssumeRoleWithSAML API. This API call is not signed, so no AWS credentials are needed.
+type STSClient struct {
+	// Endpoint is the STS endpoint url. Set it to a local stub for tests.
+	Endpoint string
+	// HTTPClient is the http client used. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// STSError is the error returned by STS.
+type STSError struct {
+	HTTPStatus int
+	Type       string `xml:"Error>Type"`
+	Code       string `xml:"Error>Code"`
+	Message    string `xml:"Error>Message"`
+	RequestID  string `xml:"RequestId"`
+}
+
+// Error return the error message
+func (e *STSError) Error() string {
+	return fmt.Sprintf("STS %d %s: %s", e.HTTPStatus, e.Code, e.Message)
+}
+
+// NewSTSClient creates a STSClient. An empty endpoint uses DefaultSTSEndpoint.
+func NewSTSClient(endpoint string, client *http.Client) (ret *STSClient) {
+	ret = new(STSClient)
+	ret.Endpoint = endpoint
+	if ret.Endpoint == "" {
+		ret.Endpoint = DefaultSTSEndpoint
+	}
+	ret.HTTPClient = client
+	return
+}
+
+// AssumeRoleWithSAML exchange the base64 encoded SAML assertion against temporary credentials of the role.
+// A duration of 0 uses the STS default (1 hour).
+func (c *STSClient) AssumeRoleWithSAML(ctx context.Context, role AwsRole, encodedAssertion string, duration time.Duration) (ret *AwsCredentials, err error) {
+	form := url.Values{}
+	form.Set("Action", "AssumeRoleWithSAML")
+	form.Set("Version", "2011-06-15")
+	form.Set("RoleArn", role.RoleARN)
+	form.Set("PrincipalArn", role.PrincipalARN)
+	form.Set("SAMLAssertion", strings.TrimSpace(encodedAssertion))
+	if duration > 0 {
+		form.Set("DurationSeconds", strconv.Itoa(int(duration/time.Second)))
+	}
+
+	request, err := http.NewRequest("POST", c.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+
+	if response.StatusCode != http.StatusOK {
+		stsErr := &STSError{HTTPStatus: response.StatusCode}
+		if xml.Unmarshal(body, stsErr) != nil || stsErr.Message == "" {
+			stsErr.Message = http.StatusText(response.StatusCode)
+		}
+		return nil, stsErr
+	}
+
+	result := struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+			Expiration      string `xml:"Expiration"`
+		} `xml:"AssumeRoleWithSAMLResult>Credentials"`
+	}{}
+	if err = xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("Invalid STS response: %w", err)
+	}
+
+	ret = new(AwsCredentials)
+	ret.AccessKeyID = result.Credentials.AccessKeyID
+	ret.SecretAccessKey = result.Credentials.SecretAccessKey
+	ret.SessionToken = result.Credentials.SessionToken
+	ret.RoleARN = role.RoleARN
+	if ret.Expiration, err = time.Parse(time.RFC3339, result.Credentials.Expiration); err != nil {
+		return nil, fmt.Errorf("Invalid STS response expiration '%s'", result.Credentials.Expiration)
+	}
+	if ret.AccessKeyID == "" || ret.SecretAccessKey == "" {
+		return nil, fmt.Errorf("Invalid STS response: no credentials")
+	}
+	return
+}
+
+// AwsRoleSelector selects the role to assume among the roles of a SAML assertion.
+//
+// Role is compared to:
+//   - the role ARN. Ex: "arn:aws:iam::123456789012:role/Admin" or "arn:aws:iam::*:role/Admin"
+//   - "<account>/<role name>", where account is the account ID or its alias. Ex: "production/Admin" or "prod*/*"
+//   - the account ID or alias only, if the account has only one role. Ex: "production"
+//
+// Glob patterns (see path.Match) are supported. An empty Role selects the only role of the assertion.
+type AwsRoleSelector struct {
+	Role string
+	// AccountAliases maps AWS account IDs to their aliases.
+	AccountAliases map[string]string
+}
+
+// Select return the role matching the selector. It fails if none or several roles match.
+func (s AwsRoleSelector) Select(roles []AwsRole) (ret AwsRole, err error) {
+	var found []AwsRole
+	for _, role := range roles {
+		if s.match(role) {
+			found = append(found, role)
+		}
+	}
+
+	switch len(found) {
+	case 1:
+		return found[0], nil
+	case 0:
+		err = fmt.Errorf("No AWS role matches '%s'. Available roles: %s", s.Role, s.describe(roles))
+	default:
+		err = fmt.Errorf("%d AWS roles match '%s': %s", len(found), s.Role, s.describe(found))
+	}
+	return
+}
+
+// match return true if the role matches the selector.
+func (s AwsRoleSelector) match(role AwsRole) bool {
+	if s.Role == "" {
+		return true
+	}
+	accounts := []string{role.AccountID()}
+	if alias, found := s.AccountAliases[role.AccountID()]; found {
+		accounts = append(accounts, alias)
+	}
+
+	candidates := []string{role.RoleARN}
+	for _, account := range accounts {
+		candidates = append(candidates, account, account+"/"+role.RoleName())
+	}
+	for _, candidate := range candidates {
+		if matched, _ := path.Match(s.Role, candidate); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// describe return the list of roles, sorted, for error messages.
+func (s AwsRoleSelector) describe(roles []AwsRole) string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		name := role.RoleARN
+		if alias, found := s.AccountAliases[role.AccountID()]; found {
+			name += " (" + alias + ")"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,139 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testAwsRole = AwsRole{
+	RoleARN:      "arn:aws:iam::123456789012:role/Admin",
+	PrincipalARN: "arn:aws:iam::123456789012:saml-provider/OneLogin",
+}
+
+func TestAssumeRoleWithSAML(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		for key, want := range map[string]string{
+			"Action":          "AssumeRoleWithSAML",
+			"RoleArn":         testAwsRole.RoleARN,
+			"PrincipalArn":    testAwsRole.PrincipalARN,
+			"SAMLAssertion":   "PHNhbWxwOlJlc3BvbnNlLz4=",
+			"DurationSeconds": "3600",
+		} {
+			if got := r.PostForm.Get(key); got != want {
+				t.Errorf("%s = %s, want %s", key, got, want)
+			}
+		}
+		fmt.Fprint(w, `<AssumeRoleWithSAMLResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithSAMLResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2020-01-02T11:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithSAMLResult>
+</AssumeRoleWithSAMLResponse>`)
+	}))
+	defer stub.Close()
+
+	creds, err := NewSTSClient(stub.URL, nil).AssumeRoleWithSAML(context.Background(), testAwsRole,
+		" PHNhbWxwOlJlc3BvbnNlLz4=\n", time.Hour)
+	if err != nil {
+		t.Fatalf("AssumeRoleWithSAML: %s", err)
+	}
+	want := AwsCredentials{
+		AccessKeyID:     "ASIAEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Date(2020, 1, 2, 11, 0, 0, 0, time.UTC),
+		RoleARN:         testAwsRole.RoleARN,
+	}
+	if *creds != want {
+		t.Errorf("credentials = %+v, want %+v", *creds, want)
+	}
+}
+
+func TestAssumeRoleWithSAMLError(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>ExpiredTokenException</Code>
+    <Message>Token must be redeemed within 5 minutes of issuance</Message>
+  </Error>
+  <RequestId>c6104cbe-af31-11e0-8154-cbc7ccf896c7</RequestId>
+</ErrorResponse>`)
+	}))
+	defer stub.Close()
+
+	_, err := NewSTSClient(stub.URL, nil).AssumeRoleWithSAML(context.Background(), testAwsRole, "assertion", 0)
+	var stsErr *STSError
+	if !errors.As(err, &stsErr) {
+		t.Fatalf("err = %v, want a STSError", err)
+	}
+	want := STSError{
+		HTTPStatus: http.StatusBadRequest,
+		Type:       "Sender",
+		Code:       "ExpiredTokenException",
+		Message:    "Token must be redeemed within 5 minutes of issuance",
+		RequestID:  "c6104cbe-af31-11e0-8154-cbc7ccf896c7",
+	}
+	if *stsErr != want {
+		t.Errorf("error = %+v, want %+v", *stsErr, want)
+	}
+}
+
+func TestAssumeRoleWithSAMLErrorWithoutBody(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer stub.Close()
+
+	_, err := NewSTSClient(stub.URL, nil).AssumeRoleWithSAML(context.Background(), testAwsRole, "assertion", 0)
+	var stsErr *STSError
+	if !errors.As(err, &stsErr) || stsErr.HTTPStatus != http.StatusServiceUnavailable ||
+		stsErr.Message != "Service Unavailable" {
+		t.Errorf("err = %v, want a 503 STSError", err)
+	}
+}
+
+func TestAwsRoleSelector(t *testing.T) {
+	roles := []AwsRole{
+		testAwsRole,
+		{RoleARN: "arn:aws:iam::123456789012:role/ReadOnly"},
+		{RoleARN: "arn:aws:iam::210987654321:role/Admin"},
+	}
+	aliases := map[string]string{"123456789012": "production"}
+	for _, test := range []struct {
+		role string
+		want string
+	}{
+		{"arn:aws:iam::210987654321:role/Admin", "arn:aws:iam::210987654321:role/Admin"},
+		{"production/ReadOnly", "arn:aws:iam::123456789012:role/ReadOnly"},
+		{"prod*/Admin", "arn:aws:iam::123456789012:role/Admin"},
+		{"210987654321", "arn:aws:iam::210987654321:role/Admin"},
+		{"*/Admin", ""},
+		{"staging/Admin", ""},
+	} {
+		role, err := AwsRoleSelector{Role: test.role, AccountAliases: aliases}.Select(roles)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%s: selected %s, want an error", test.role, role.RoleARN)
+			}
+			continue
+		}
+		if err != nil || role.RoleARN != test.want {
+			t.Errorf("%s: selected %s, %v, want %s", test.role, role.RoleARN, err, test.want)
+		}
+	}
+}
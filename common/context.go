@@ -0,0 +1,20 @@
+package common
+
+import (
+	"context"
+	"time"
+)
+
+// SleepWithContext wait for the given duration, unless ctx is done before.
+// It returns ctx.Err() if ctx is done first.
+func SleepWithContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
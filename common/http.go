@@ -2,6 +2,7 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -13,7 +14,15 @@ type Headers map[string]string
 
 // Get data from a API service and decode json value automatically.
 func Get(url string, data interface{}) (response *http.Response, err error) {
-	response, err = http.Get(url)
+	return GetWithClient(http.DefaultClient, url, data)
+}
+
+// GetWithClient is Get executed with the given http client.
+func GetWithClient(client *http.Client, url string, data interface{}) (response *http.Response, err error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err = client.Get(url)
 	if err != nil {
 		return
 	}
@@ -27,10 +36,18 @@ func Get(url string, data interface{}) (response *http.Response, err error) {
 
 // Post to an API endpoint, json encoding and decoding req and data respectivelly.
 func Post(url string, req interface{}, data interface{}) (response *http.Response, err error) {
+	return PostWithClient(http.DefaultClient, url, req, data)
+}
+
+// PostWithClient is Post executed with the given http client.
+func PostWithClient(client *http.Client, url string, req interface{}, data interface{}) (response *http.Response, err error) {
 	var reqBody []byte
 
+	if client == nil {
+		client = http.DefaultClient
+	}
 	reqBody, err = json.Marshal(req)
-	response, err = http.Post(url, "test/json", bytes.NewBuffer(reqBody))
+	response, err = client.Post(url, "application/json", bytes.NewBuffer(reqBody))
 	if err != nil {
 		return
 	}
@@ -44,6 +61,34 @@ func Post(url string, req interface{}, data interface{}) (response *http.Respons
 
 // Request execute a request with headers and method setup
 func Request(method string, headers Headers, url string, req interface{}, data interface{}) (response *http.Response, err error) {
+	return RequestWithContext(context.Background(), method, headers, url, req, data)
+}
+
+// RequestWithContext execute a request with headers and method setup.
+// The request is cancelled when ctx is done.
+func RequestWithContext(ctx context.Context, method string, headers Headers, url string, req interface{}, data interface{}) (response *http.Response, err error) {
+	return RequestWithClient(ctx, nil, method, headers, url, req, data)
+}
+
+// RequestWithClient execute a request with headers and method setup, thanks to the given http client.
+// If client is nil, http.DefaultClient is used.
+func RequestWithClient(ctx context.Context, client *http.Client, method string, headers Headers, url string, req interface{}, data interface{}) (response *http.Response, err error) {
+	var buf []byte
+
+	response, buf, err = Do(ctx, client, method, headers, url, req)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(buf, data)
+
+	return
+}
+
+// Do execute a request with headers and method setup, thanks to the given http client.
+// It returns the response and the response body, not decoded.
+// If client is nil, http.DefaultClient is used.
+func Do(ctx context.Context, client *http.Client, method string, headers Headers, url string, req interface{}) (response *http.Response, body []byte, err error) {
 	var request *http.Request
 
 	if method == "POST" || method == "PUT" {
@@ -62,6 +107,7 @@ func Request(method string, headers Headers, url string, req interface{}, data i
 	if err != nil {
 		return
 	}
+	request = request.WithContext(ctx)
 
 	// Set Request header from headers
 	for k, v := range headers {
@@ -69,18 +115,17 @@ func Request(method string, headers Headers, url string, req interface{}, data i
 	}
 
 	//fmt.Printf("request:\n%s\n", request)
-	client := &http.Client{}
+	if client == nil {
+		client = http.DefaultClient
+	}
 	response, err = client.Do(request)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 
-	//err = json.NewDecoder(response.Body).Decode(data)
-
-	buf, _ := ioutil.ReadAll(response.Body)
-	err = json.Unmarshal(buf, data)
+	body, err = ioutil.ReadAll(response.Body)
 
 	return
 }
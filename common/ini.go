@@ -0,0 +1,184 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// IniFile is an ini file kept line by line, so that comments, other sections and formatting are preserved when it is
+// updated.
+type IniFile struct {
+	lines []string
+}
+
+// ReadIniFile reads an ini file. A missing file returns an empty IniFile.
+func ReadIniFile(path string) (ret *IniFile, err error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ParseIni(nil), nil
+	}
+	if err != nil {
+		return
+	}
+	return ParseIni(data), nil
+}
+
+// ParseIni creates an IniFile from the ini data.
+func ParseIni(data []byte) (ret *IniFile) {
+	ret = new(IniFile)
+	content := strings.Replace(string(data), "\r\n", "\n", -1)
+	if content != "" {
+		ret.lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+	return
+}
+
+// Sections return the list of section names, in file order.
+func (f *IniFile) Sections() (ret []string) {
+	for _, line := range f.lines {
+		if name, isSection := iniSection(line); isSection {
+			ret = append(ret, name)
+		}
+	}
+	return
+}
+
+// Get return the value of key in section.
+func (f *IniFile) Get(section, key string) (value string, found bool) {
+	start, end := f.sectionRange(section)
+	if start < 0 {
+		return
+	}
+	for _, line := range f.lines[start+1 : end] {
+		if k, v, isKey := iniKey(line); isKey && k == key {
+			return v, true
+		}
+	}
+	return
+}
+
+// Set updates or adds the keys of the section, and creates the section if missing.
+// Existing keys are updated in place. Other keys, comments and sections are kept.
+func (f *IniFile) Set(section string, values map[string]string) {
+	start, end := f.sectionRange(section)
+	if start < 0 {
+		if len(f.lines) > 0 && strings.TrimSpace(f.lines[len(f.lines)-1]) != "" {
+			f.lines = append(f.lines, "")
+		}
+		f.lines = append(f.lines, "["+section+"]")
+		start, end = len(f.lines)-1, len(f.lines)
+	}
+
+	updated := make(map[string]bool)
+	for index := start + 1; index < end; index++ {
+		if key, _, isKey := iniKey(f.lines[index]); isKey {
+			if value, found := values[key]; found {
+				f.lines[index] = key + " = " + value
+				updated[key] = true
+			}
+		}
+	}
+
+	// New keys are added after the last key of the section, sorted.
+	var added []string
+	for key, value := range values {
+		if !updated[key] {
+			added = append(added, key+" = "+value)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	sort.Strings(added)
+
+	insert := start + 1
+	for index := start + 1; index < end; index++ {
+		if _, _, isKey := iniKey(f.lines[index]); isKey {
+			insert = index + 1
+		}
+	}
+	lines := append([]string{}, f.lines[:insert]...)
+	lines = append(lines, added...)
+	f.lines = append(lines, f.lines[insert:]...)
+}
+
+// Delete removes the keys from the section.
+func (f *IniFile) Delete(section string, keys ...string) {
+	start, end := f.sectionRange(section)
+	if start < 0 {
+		return
+	}
+	lines := append([]string{}, f.lines[:start+1]...)
+	for _, line := range f.lines[start+1 : end] {
+		if key, _, isKey := iniKey(line); isKey && inList(key, keys) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	f.lines = append(lines, f.lines[end:]...)
+}
+
+// Bytes return the ini file content.
+func (f *IniFile) Bytes() []byte {
+	if len(f.lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(f.lines, "\n") + "\n")
+}
+
+// Write writes the ini file atomically, with the given permissions.
+func (f *IniFile) Write(path string, perm os.FileMode) error {
+	return WriteFileAtomic(path, f.Bytes(), perm)
+}
+
+// sectionRange return the line index of the section header and the index of the line after the section.
+// start is -1 if the section does not exist.
+func (f *IniFile) sectionRange(section string) (start, end int) {
+	start = -1
+	for index, line := range f.lines {
+		name, isSection := iniSection(line)
+		if !isSection {
+			continue
+		}
+		if start >= 0 {
+			return start, index
+		}
+		if name == section {
+			start = index
+		}
+	}
+	return start, len(f.lines)
+}
+
+// iniSection return the section name if the line is a section header.
+func iniSection(line string) (name string, isSection bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		return strings.TrimSpace(line[1 : len(line)-1]), true
+	}
+	return
+}
+
+// iniKey return the key and value if the line is a key/value pair.
+func iniKey(line string) (key, value string, isKey bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+		return
+	}
+	index := strings.Index(line, "=")
+	if index <= 0 {
+		return
+	}
+	return strings.TrimSpace(line[:index]), strings.TrimSpace(line[index+1:]), true
+}
+
+func inList(value string, list []string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
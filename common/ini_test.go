@@ -0,0 +1,89 @@
+package common
+
+import (
+	"testing"
+)
+
+const testIni = `# AWS credentials
+[default]
+aws_access_key_id = OLD
+; keep this comment
+aws_secret_access_key = old-secret
+
+[other]
+aws_access_key_id = OTHER
+`
+
+func TestIniFileSetPreservesComments(t *testing.T) {
+	file := ParseIni([]byte(testIni))
+	file.Set("default", map[string]string{
+		"aws_access_key_id": "NEW",
+		"aws_session_token": "token",
+	})
+
+	want := `# AWS credentials
+[default]
+aws_access_key_id = NEW
+; keep this comment
+aws_secret_access_key = old-secret
+aws_session_token = token
+
+[other]
+aws_access_key_id = OTHER
+`
+	if got := string(file.Bytes()); got != want {
+		t.Errorf("ini =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestIniFileSetNewSection(t *testing.T) {
+	file := ParseIni([]byte(testIni))
+	file.Set("profile prod", map[string]string{"region": "eu-west-1", "output": "json"})
+
+	want := testIni + `
+[profile prod]
+output = json
+region = eu-west-1
+`
+	if got := string(file.Bytes()); got != want {
+		t.Errorf("ini =\n%s\nwant\n%s", got, want)
+	}
+	if sections := file.Sections(); len(sections) != 3 || sections[2] != "profile prod" {
+		t.Errorf("Sections = %v", sections)
+	}
+
+	empty := ParseIni(nil)
+	empty.Set("default", map[string]string{"region": "us-east-1"})
+	if got := string(empty.Bytes()); got != "[default]\nregion = us-east-1\n" {
+		t.Errorf("ini = %q", got)
+	}
+}
+
+func TestIniFileGetAndDelete(t *testing.T) {
+	file := ParseIni([]byte(testIni))
+	if value, found := file.Get("other", "aws_access_key_id"); !found || value != "OTHER" {
+		t.Errorf("Get = %s, %t, want OTHER", value, found)
+	}
+	if _, found := file.Get("default", "aws_session_token"); found {
+		t.Error("Get found a missing key")
+	}
+
+	file.Delete("default", "aws_access_key_id", "missing")
+	if _, found := file.Get("default", "aws_access_key_id"); found {
+		t.Error("key not deleted")
+	}
+	if _, found := file.Get("other", "aws_access_key_id"); !found {
+		t.Error("key of another section deleted")
+	}
+	want := `# AWS credentials
+[default]
+; keep this comment
+aws_secret_access_key = old-secret
+
+[other]
+aws_access_key_id = OTHER
+`
+	if got := string(file.Bytes()); got != want {
+		t.Errorf("ini =\n%s\nwant\n%s", got, want)
+	}
+}
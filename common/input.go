@@ -3,6 +3,7 @@ package common
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strconv"
@@ -12,40 +13,75 @@ import (
 // Select ask user to enter the proper interger choice.
 // TODO: to move to app code to make this module non interactive.
 func Select(from, until int) int {
-	reader := bufio.NewReader(os.Stdin)
+	selected, err := SelectFrom(bufio.NewReader(os.Stdin), os.Stdout, from, until)
+	if err != nil {
+		log.Fatalf("Unable to retrieve the data input. %s", err)
+	}
+	return selected
+}
 
-	fmt.Printf("Enter the value (%d ... %d): ", from, until)
+// SelectFrom ask user to enter the proper interger choice, reading from reader and writing messages to out.
+// It returns an error if the input cannot be read (EOF included).
+func SelectFrom(reader *bufio.Reader, out io.Writer, from, until int) (selected int, err error) {
+	fmt.Fprintf(out, "Enter the value (%d ... %d): ", from, until)
 	for {
-		ID, err := reader.ReadString('\n')
+		var ID string
+		ID, err = reader.ReadString('\n')
 		if err != nil {
-			log.Fatalf("Unable to retrieve the data input. %s", err)
+			return
 		}
 
-		ID = strings.Trim(ID, "\n")
+		ID = strings.Trim(ID, "\r\n")
 
-		if selected, err := strconv.Atoi(ID); err == nil && selected >= from && selected <= until {
-			return selected
+		if v, err := strconv.Atoi(ID); err == nil && v >= from && v <= until {
+			return v, nil
 		}
-		fmt.Printf("Your choice must be between %d and %d. You entered '%s'. Please select proper one.\n", from, until, ID)
-		fmt.Print("Enter the value: ")
+		fmt.Fprintf(out, "Your choice must be between %d and %d. You entered '%s'. Please select proper one.\n", from, until, ID)
+		fmt.Fprint(out, "Enter the value: ")
 	}
 }
 
 // GetNumber ask to enter a number
 func GetNumber(mess string) (selected int) {
-	reader := bufio.NewReader(os.Stdin)
+	selected, err := GetNumberFrom(bufio.NewReader(os.Stdin), os.Stdout, mess)
+	if err != nil {
+		log.Fatalf("Unable to retrieve the data input. %s", err)
+	}
+	return selected
+}
+
+// GetNumberFrom ask to enter a number, reading from reader and writing messages to out.
+// It returns an error if the input cannot be read (EOF included).
+func GetNumberFrom(reader *bufio.Reader, out io.Writer, mess string) (selected int, err error) {
+	fmt.Fprint(out, mess)
+	for {
+		var ID string
+		ID, err = reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		var errConv error
+		selected, errConv = strconv.Atoi(strings.Trim(ID, " \r\n"))
+		if errConv == nil {
+			return selected, nil
+		}
+		fmt.Fprintf(out, "Please enter a number. %s\n", errConv)
+		fmt.Fprint(out, mess)
+	}
+}
 
-	fmt.Print(mess)
+// GetStringFrom ask to enter a non empty string, reading from reader and writing messages to out.
+// Spaces around the string are removed. It returns an error if the input cannot be read (EOF included).
+func GetStringFrom(reader *bufio.Reader, out io.Writer, mess string) (value string, err error) {
+	fmt.Fprint(out, mess)
 	for {
-		ID, err := reader.ReadString('\n')
+		value, err = reader.ReadString('\n')
 		if err != nil {
-			log.Fatalf("Unable to retrieve the data input. %s", err)
+			return
 		}
-		selected, err = strconv.Atoi(strings.Trim(ID, " \n"))
-		if err == nil {
-			return selected
+		if value = strings.TrimSpace(value); value != "" {
+			return value, nil
 		}
-		fmt.Printf("Please enter a number. %s\n", err)
-		fmt.Print(mess)
+		fmt.Fprint(out, mess)
 	}
 }
@@ -0,0 +1,162 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/op/go-logging"
+)
+
+// OneLoginPasswordEnv is the environment variable giving the OneLogin password to CredentialProcess.
+const OneLoginPasswordEnv = "ONELOGIN_PASSWORD"
+
+// CredentialProcessOutput is the JSON document expected from an AWS credential_process.
+// See https://docs.aws.amazon.com/cli/latest/topic/config-vars.html#sourcing-credentials-from-external-processes
+type CredentialProcessOutput struct {
+	Version         int
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      string `json:",omitempty"`
+}
+
+// NewCredentialProcessOutput creates the credential_process document of the credentials.
+func NewCredentialProcessOutput(creds *AwsCredentials) (ret *CredentialProcessOutput) {
+	ret = new(CredentialProcessOutput)
+	ret.Version = 1
+	ret.AccessKeyID = creds.AccessKeyID
+	ret.SecretAccessKey = creds.SecretAccessKey
+	ret.SessionToken = creds.SessionToken
+	if !creds.Expiration.IsZero() {
+		ret.Expiration = creds.Expiration.UTC().Format(time.RFC3339)
+	}
+	return
+}
+
+// CredentialProcess gets the AWS credentials of an OL-AWS configuration profile, to be used as AWS credential_process:
+//
+//	[profile prod]
+//	credential_process = my-tool credential-process --profile prod
+//
+// Credentials are cached on disk and returned while they are valid, so the MFA is not requested on each call.
+// As stdout is read by AWS, MFA interaction is done on stderr.
+type CredentialProcess struct {
+	Config *OLAWSConfig
+	// Service is the OneLogin service. Created from Config if nil.
+	Service *Service
+	// Password return the OneLogin password of the user. By default, it is read from $ONELOGIN_PASSWORD.
+	Password func(username string) (string, error)
+	// Cache is the AWS credentials cache. nil disables the cache.
+	Cache *AwsCredentialCache
+}
+
+// NewCredentialProcess creates a CredentialProcess for the configuration.
+func NewCredentialProcess(config *OLAWSConfig) (ret *CredentialProcess) {
+	ret = new(CredentialProcess)
+	ret.Config = config
+	ret.Cache = NewAwsCredentialCache("")
+	return
+}
+
+// Run writes to out the credential_process JSON document of the profile credentials.
+func (p *CredentialProcess) Run(ctx context.Context, profile string, out io.Writer) (err error) {
+	creds, err := p.Credentials(ctx, profile)
+	if err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(NewCredentialProcessOutput(creds), "", "  ")
+	if err != nil {
+		return
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return
+}
+
+// Credentials return the profile credentials, from the cache if they are still valid.
+// Otherwise, the user is authenticated on OneLogin and the profile role is assumed.
+func (p *CredentialProcess) Credentials(ctx context.Context, profile string) (ret *AwsCredentials, err error) {
+	if p == nil || p.Config == nil {
+		return nil, errors.New("CredentialProcess configuration is nil")
+	}
+	olProfile, err := p.Config.Profile(profile)
+	if err != nil {
+		return
+	}
+	if p.Config.Username == "" {
+		return nil, errors.New("username is required in the configuration to get AWS credentials")
+	}
+
+	if ret = p.Cache.Get(p.cacheKey(olProfile)); ret != nil {
+		logger.Infof("Using cached AWS credentials of profile %s, valid until %s", profile, ret.Expiration)
+		return
+	}
+
+	service := p.Service
+	if service == nil {
+		if service, err = p.Config.NewService(logging.WARNING); err != nil {
+			return
+		}
+		service.SetMFAPrompter(NewTerminalPrompter(os.Stdin, os.Stderr))
+	}
+	password, err := p.password()
+	if err != nil {
+		return
+	}
+
+	assertion, err := service.SAMLAuthenticateOTPWithContext(ctx, p.Config.Username, password, olProfile.AppID, "", "", -1)
+	if err != nil {
+		return
+	}
+	ret, err = service.AssumeAwsRoleWithContext(ctx, assertion, AwsRoleSelector{Role: olProfile.RoleARN}, olProfile.Duration)
+	if err != nil {
+		return
+	}
+	if p.Cache != nil {
+		if err = p.Cache.Put(p.cacheKey(olProfile), ret); err != nil {
+			logger.Warningf("Unable to cache AWS credentials of profile %s: %s", profile, err)
+			err = nil
+		}
+	}
+	return
+}
+
+// Invalidate removes the cached credentials of the profile, so the next call authenticates again on OneLogin.
+func (p *CredentialProcess) Invalidate(profile string) (err error) {
+	if p == nil || p.Config == nil {
+		return errors.New("CredentialProcess configuration is nil")
+	}
+	olProfile, err := p.Config.Profile(profile)
+	if err != nil {
+		return
+	}
+	if p.Cache == nil {
+		return nil
+	}
+	return p.Cache.Invalidate(p.cacheKey(olProfile))
+}
+
+// cacheKey return the cache key of the profile credentials.
+func (p *CredentialProcess) cacheKey(profile *OLAWSProfile) AwsCredentialCacheKey {
+	return AwsCredentialCacheKey{
+		Subdomain: p.Config.Subdomain,
+		Username:  p.Config.Username,
+		AppID:     profile.AppID,
+		Role:      profile.RoleARN,
+	}
+}
+
+// password return the user password from the Password function or $ONELOGIN_PASSWORD.
+func (p *CredentialProcess) password() (string, error) {
+	if p.Password != nil {
+		return p.Password(p.Config.Username)
+	}
+	if password := os.Getenv(OneLoginPasswordEnv); password != "" {
+		return password, nil
+	}
+	return "", fmt.Errorf("no password given for %s. Set %s", p.Config.Username, OneLoginPasswordEnv)
+}
@@ -1,5 +1,8 @@
 module github.com/clarsonneur/onelogin
 
-go 1.12
+go 1.13
 
-require github.com/op/go-logging v0.0.0-20160315200505-970db520ece7
+require (
+	github.com/op/go-logging v0.0.0-20160315200505-970db520ece7
+	gopkg.in/yaml.v2 v2.4.0
+)
@@ -0,0 +1,103 @@
+package onelogin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+// ErrMFADeviceNotFound is returned when no MFA device of the user matches the MFADevicePolicy.
+var ErrMFADeviceNotFound = errors.New("onelogin: no MFA device matching the policy")
+
+// MFADevicePolicy selects the MFA device used by Service.SAMLAuthenticateOTP, without user interaction.
+// See Service.SetMFADevicePolicy.
+//
+// Criteria are checked in this order, and the first matching device is selected:
+//   - DeviceID, if not 0,
+//   - DeviceTypes, in preference order,
+//   - the first push capable device, if FirstPushCapable is true.
+type MFADevicePolicy struct {
+	DeviceID    int
+	DeviceTypes []string
+	// FirstPushCapable selects the first device accepting push notifications (OneLogin Protect, Duo).
+	FirstPushCapable bool
+}
+
+// NewMFADevicePolicyByID creates a MFADevicePolicy selecting the device with this ID.
+func NewMFADevicePolicyByID(deviceID int) (ret *MFADevicePolicy) {
+	ret = new(MFADevicePolicy)
+	ret.DeviceID = deviceID
+	return
+}
+
+// NewMFADevicePolicyByType creates a MFADevicePolicy selecting the first device of the types given, in preference
+// order.
+func NewMFADevicePolicyByType(deviceTypes ...string) (ret *MFADevicePolicy) {
+	ret = new(MFADevicePolicy)
+	ret.DeviceTypes = deviceTypes
+	return
+}
+
+// NewMFADevicePolicyFirstPush creates a MFADevicePolicy selecting the first push capable device.
+func NewMFADevicePolicyFirstPush() (ret *MFADevicePolicy) {
+	ret = new(MFADevicePolicy)
+	ret.FirstPushCapable = true
+	return
+}
+
+// Select return the index of the device to use. ErrMFADeviceNotFound is returned with the list of available
+// devices if none matches.
+func (p *MFADevicePolicy) Select(devices []api.SAMLAssertionDevice) (int, error) {
+	if p == nil {
+		return -1, errors.New("MFADevicePolicy is nil")
+	}
+
+	if p.DeviceID != 0 {
+		for index, device := range devices {
+			if device.DeviceID == p.DeviceID {
+				return index, nil
+			}
+		}
+	}
+	for _, deviceType := range p.DeviceTypes {
+		for index, device := range devices {
+			if strings.EqualFold(device.DeviceType, deviceType) {
+				return index, nil
+			}
+		}
+	}
+	if p.FirstPushCapable {
+		for index, device := range devices {
+			if IsPushCapable(device.DeviceType) {
+				return index, nil
+			}
+		}
+	}
+
+	available := make([]string, 0, len(devices))
+	for _, device := range devices {
+		available = append(available, fmt.Sprintf("%d (%s)", device.DeviceID, device.DeviceType))
+	}
+	return -1, fmt.Errorf("No MFA device with %s. Available devices: %s: %w", p, strings.Join(available, ", "),
+		ErrMFADeviceNotFound)
+}
+
+// String describes the policy criteria.
+func (p *MFADevicePolicy) String() string {
+	var criteria []string
+	if p.DeviceID != 0 {
+		criteria = append(criteria, fmt.Sprintf("device ID %d", p.DeviceID))
+	}
+	if len(p.DeviceTypes) > 0 {
+		criteria = append(criteria, fmt.Sprintf("device type '%s'", strings.Join(p.DeviceTypes, "', '")))
+	}
+	if p.FirstPushCapable {
+		criteria = append(criteria, "push capable device")
+	}
+	if len(criteria) == 0 {
+		return "no criteria"
+	}
+	return strings.Join(criteria, " or ")
+}
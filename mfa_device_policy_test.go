@@ -0,0 +1,59 @@
+package onelogin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+var testDevices = []api.SAMLAssertionDevice{
+	{DeviceID: 10, DeviceType: DeviceTypeOneLoginSMS},
+	{DeviceID: 20, DeviceType: DeviceTypeGoogleAuthenticator},
+	{DeviceID: 30, DeviceType: DeviceTypeDuo},
+	{DeviceID: 40, DeviceType: DeviceTypeOneLoginProtect},
+}
+
+func TestMFADevicePolicySelect(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		policy *MFADevicePolicy
+		index  int
+	}{
+		{"ID", NewMFADevicePolicyByID(40), 3},
+		{"ID over type", &MFADevicePolicy{DeviceID: 20, DeviceTypes: []string{DeviceTypeOneLoginSMS}}, 1},
+		{"unknown ID, then type", &MFADevicePolicy{DeviceID: 99, DeviceTypes: []string{DeviceTypeDuo}}, 2},
+		{"type", NewMFADevicePolicyByType(DeviceTypeGoogleAuthenticator), 1},
+		{"type preference order", NewMFADevicePolicyByType(DeviceTypeYubiKey, DeviceTypeOneLoginProtect, DeviceTypeOneLoginSMS), 3},
+		{"type case insensitive", NewMFADevicePolicyByType("google authenticator"), 1},
+		{"type over push", &MFADevicePolicy{DeviceTypes: []string{"ONELOGIN SMS"}, FirstPushCapable: true}, 0},
+		{"first push capable", NewMFADevicePolicyFirstPush(), 2},
+		{"unknown type, then push", &MFADevicePolicy{DeviceTypes: []string{DeviceTypeYubiKey}, FirstPushCapable: true}, 2},
+	} {
+		index, err := test.policy.Select(testDevices)
+		if err != nil || index != test.index {
+			t.Errorf("%s: Select = %d, %v, want %d", test.name, index, err, test.index)
+		}
+	}
+}
+
+func TestMFADevicePolicyNotFound(t *testing.T) {
+	_, err := NewMFADevicePolicyByType(DeviceTypeYubiKey).Select(testDevices)
+	if !errors.Is(err, ErrMFADeviceNotFound) {
+		t.Fatalf("err = %v, want ErrMFADeviceNotFound", err)
+	}
+	for _, expected := range []string{
+		"device type 'Yubico YubiKey'",
+		"Available devices: 10 (OneLogin SMS), 20 (Google Authenticator), 30 (Duo Security), 40 (OneLogin Protect)",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("err = %s, want it to contain %q", err, expected)
+		}
+	}
+
+	// No push capable device.
+	if _, err = NewMFADevicePolicyFirstPush().Select(testDevices[:2]); !errors.Is(err, ErrMFADeviceNotFound) {
+		t.Errorf("err = %v, want ErrMFADeviceNotFound", err)
+	}
+}
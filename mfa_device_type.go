@@ -0,0 +1,67 @@
+package onelogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MFA device types, as reported by OneLogin.
+const (
+	DeviceTypeOneLoginProtect     = "OneLogin Protect"
+	DeviceTypeOneLoginSMS         = "OneLogin SMS"
+	DeviceTypeOneLoginVoice       = "OneLogin Voice"
+	DeviceTypeOneLoginEmail       = "OneLogin Email"
+	DeviceTypeGoogleAuthenticator = "Google Authenticator"
+	DeviceTypeYubiKey             = "Yubico YubiKey"
+	DeviceTypeDuo                 = "Duo Security"
+	DeviceTypeRSASecurID          = "RSA SecurID"
+)
+
+// YubiKeyOTPLength is the length of a YubiKey OTP: 12 characters of public ID and 32 of encrypted token.
+const YubiKeyOTPLength = 44
+
+// yubiKeyModhex are the characters of a YubiKey OTP (modhex encoding).
+const yubiKeyModhex = "cbdefghijklnrtuv"
+
+// pushDeviceTypes are the MFA device types which can approve an authentication with a push notification
+// (or a phone callback, for Duo).
+var pushDeviceTypes = []string{DeviceTypeOneLoginProtect, DeviceTypeDuo}
+
+// deliveredOTPDeviceTypes are the MFA device types receiving the OTP code from OneLogin.
+var deliveredOTPDeviceTypes = []string{DeviceTypeOneLoginSMS, DeviceTypeOneLoginVoice, DeviceTypeOneLoginEmail}
+
+// numericOTPDeviceTypes are the MFA device types with digits only OTP codes.
+var numericOTPDeviceTypes = []string{
+	DeviceTypeOneLoginProtect, DeviceTypeOneLoginSMS, DeviceTypeOneLoginVoice, DeviceTypeOneLoginEmail,
+	DeviceTypeGoogleAuthenticator, DeviceTypeDuo,
+}
+
+// IsPushCapable return true if the device type can approve an authentication with a push notification.
+func IsPushCapable(deviceType string) bool {
+	return inStrings(pushDeviceTypes, deviceType)
+}
+
+// isOTPDelivered return true if OneLogin sends the OTP code to the device (SMS, voice call or email).
+func isOTPDelivered(deviceType string) bool {
+	return inStrings(deliveredOTPDeviceTypes, deviceType)
+}
+
+// ValidateOTP checks the OTP code format for the device type. Codes are strings: they may start with 0 and
+// YubiKey or RSA SecurID codes are not numbers.
+func ValidateOTP(deviceType, otp string) error {
+	if otp == "" {
+		return fmt.Errorf("The %s OTP code is empty", deviceType)
+	}
+	switch {
+	case deviceType == DeviceTypeYubiKey:
+		if len(otp) != YubiKeyOTPLength || strings.Trim(otp, yubiKeyModhex) != "" {
+			return fmt.Errorf("Invalid YubiKey OTP. It must be %d characters long, from '%s'", YubiKeyOTPLength,
+				yubiKeyModhex)
+		}
+	case inStrings(numericOTPDeviceTypes, deviceType):
+		if strings.Trim(otp, "0123456789") != "" {
+			return fmt.Errorf("Invalid %s OTP code. It must contain digits only", deviceType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package onelogin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateOTP(t *testing.T) {
+	yubiKeyOTP := "cccjgjgkhcbb" + strings.Repeat("cbdefghijklnrtuv", 2)
+
+	for _, test := range []struct {
+		name, deviceType, otp string
+		valid                 bool
+	}{
+		{"YubiKey", DeviceTypeYubiKey, yubiKeyOTP, true},
+		{"YubiKey too short", DeviceTypeYubiKey, yubiKeyOTP[1:], false},
+		{"YubiKey too long", DeviceTypeYubiKey, yubiKeyOTP + "c", false},
+		{"YubiKey not modhex", DeviceTypeYubiKey, "a" + yubiKeyOTP[1:], false},
+		{"YubiKey digits", DeviceTypeYubiKey, strings.Repeat("1", YubiKeyOTPLength), false},
+		{"Google Authenticator leading zero", DeviceTypeGoogleAuthenticator, "012345", true},
+		{"Google Authenticator letters", DeviceTypeGoogleAuthenticator, "12345a", false},
+		{"OneLogin Protect", DeviceTypeOneLoginProtect, "123456", true},
+		{"OneLogin SMS", DeviceTypeOneLoginSMS, "1234567", true},
+		{"OneLogin Voice spaces", DeviceTypeOneLoginVoice, "123 456", false},
+		{"OneLogin Email", DeviceTypeOneLoginEmail, "987654", true},
+		{"Duo letters", DeviceTypeDuo, "abcdef", false},
+		{"RSA SecurID", DeviceTypeRSASecurID, "pin12345678", true},
+		{"Unknown type", "Other", "any code", true},
+		{"Empty", DeviceTypeGoogleAuthenticator, "", false},
+		{"Empty unknown type", "Other", "", false},
+	} {
+		err := ValidateOTP(test.deviceType, test.otp)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: ValidateOTP(%s, %q) = %v, want valid %t", test.name, test.deviceType, test.otp, err, test.valid)
+		}
+	}
+}
+
+func TestDeviceTypeCapabilities(t *testing.T) {
+	for _, deviceType := range []string{DeviceTypeOneLoginProtect, DeviceTypeDuo} {
+		if !IsPushCapable(deviceType) || isOTPDelivered(deviceType) {
+			t.Errorf("%s: want push capable, OTP not delivered", deviceType)
+		}
+	}
+	for _, deviceType := range []string{DeviceTypeOneLoginSMS, DeviceTypeOneLoginVoice, DeviceTypeOneLoginEmail} {
+		if IsPushCapable(deviceType) || !isOTPDelivered(deviceType) {
+			t.Errorf("%s: want OTP delivered, not push capable", deviceType)
+		}
+	}
+	for _, deviceType := range []string{DeviceTypeGoogleAuthenticator, DeviceTypeYubiKey, DeviceTypeRSASecurID} {
+		if IsPushCapable(deviceType) || isOTPDelivered(deviceType) {
+			t.Errorf("%s: want neither push capable nor OTP delivered", deviceType)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package onelogin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/clarsonneur/onelogin/api"
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// MFAPrompter is used by Service.SAMLAuthenticateOTP to interact with the user when a MFA is required.
+//
+// TerminalPrompter is the default implementation. ScriptedPrompter can be used for automation and tests.
+type MFAPrompter interface {
+	// SelectDevice returns the index of the device to use from the list of user devices.
+	SelectDevice(devices []api.SAMLAssertionDevice) (int, error)
+	// GetOTP returns the OTP code to verify for the given device.
+	// This is a string, as codes may start with 0 or may not be numbers (YubiKey).
+	GetOTP(device api.SAMLAssertionDevice) (string, error)
+	// PushPending is called each time OneLogin reports a push notification still waiting for approval.
+	PushPending(device api.SAMLAssertionDevice)
+	// Progress reports an authentication step to the user.
+	Progress(message string)
+}
+
+// TerminalPrompter is the interactive MFAPrompter. It reads user input from a reader (usually os.Stdin)
+// and prints messages to a writer (usually os.Stdout)
+type TerminalPrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewTerminalPrompter creates a TerminalPrompter. nil in or out are replaced by os.Stdin and os.Stdout.
+func NewTerminalPrompter(in io.Reader, out io.Writer) (ret *TerminalPrompter) {
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stdout
+	}
+	ret = new(TerminalPrompter)
+	ret.in = bufio.NewReader(in)
+	ret.out = out
+	return
+}
+
+// SelectDevice display the list of devices and ask the user to select one.
+func (p *TerminalPrompter) SelectDevice(devices []api.SAMLAssertionDevice) (int, error) {
+	fmt.Fprint(p.out, "Authenticate using one of these devices:\n")
+	fmt.Fprint(p.out, "-----------------------------------------------------------------------\n")
+	for index, device := range devices {
+		fmt.Fprintf(p.out, " %d | %s\n", index, device.DeviceType)
+	}
+	fmt.Fprint(p.out, "-----------------------------------------------------------------------\n")
+
+	return common.SelectFrom(p.in, p.out, 0, len(devices)-1)
+}
+
+// GetOTP ask the user to enter the OTP code of the device, until the code format is valid.
+func (p *TerminalPrompter) GetOTP(device api.SAMLAssertionDevice) (otp string, err error) {
+	for {
+		if otp, err = common.GetStringFrom(p.in, p.out, otpPromptMessage(device)); err != nil {
+			return
+		}
+		errValid := ValidateOTP(device.DeviceType, otp)
+		if errValid == nil {
+			return
+		}
+		fmt.Fprintf(p.out, "%s\n", errValid)
+	}
+}
+
+// PushPending display a progress dot.
+func (p *TerminalPrompter) PushPending(device api.SAMLAssertionDevice) {
+	fmt.Fprint(p.out, ".")
+}
+
+// Progress display the message on a new line.
+func (p *TerminalPrompter) Progress(message string) {
+	fmt.Fprintf(p.out, "%s\n", message)
+}
+
+// otpPromptMessage return the message to display to ask for an OTP code.
+func otpPromptMessage(device api.SAMLAssertionDevice) string {
+	switch device.DeviceType {
+	case DeviceTypeOneLoginSMS:
+		return "Enter the SMS OTP code received:"
+	case DeviceTypeOneLoginVoice:
+		return "Enter the OTP code given by the voice call:"
+	case DeviceTypeOneLoginEmail:
+		return "Enter the OTP code received by email:"
+	case DeviceTypeOneLoginProtect:
+		return "Enter the OneProtect OTP code from your mobile application:"
+	case DeviceTypeGoogleAuthenticator:
+		return "Enter the Google Authenticator OTP code:"
+	case DeviceTypeYubiKey:
+		return "Touch your YubiKey:"
+	case DeviceTypeDuo:
+		return "Enter a Duo passcode:"
+	case DeviceTypeRSASecurID:
+		return "Enter your RSA SecurID passcode (PIN followed by the token code):"
+	}
+	return fmt.Sprintf("Enter the %s OTP code:", device.DeviceType)
+}
+
+// ScriptedPrompter is a non interactive MFAPrompter.
+// It selects the device from DeviceType or DeviceIndex and returns the OTP codes from OTPs, in order.
+// Push notifications and progress messages are recorded.
+type ScriptedPrompter struct {
+	// DeviceType select the first device of that type. If empty, DeviceIndex is used.
+	DeviceType  string
+	DeviceIndex int
+	// OTPs is the list of OTP codes to return, consumed by GetOTP.
+	OTPs []string
+
+	PushCount int
+	Messages  []string
+}
+
+// NewScriptedPrompter creates a ScriptedPrompter selecting the device at deviceIndex and returning the given OTP codes.
+func NewScriptedPrompter(deviceIndex int, otps ...string) (ret *ScriptedPrompter) {
+	ret = new(ScriptedPrompter)
+	ret.DeviceIndex = deviceIndex
+	ret.OTPs = otps
+	return
+}
+
+// SelectDevice returns the device index as scripted.
+func (p *ScriptedPrompter) SelectDevice(devices []api.SAMLAssertionDevice) (int, error) {
+	if p.DeviceType != "" {
+		for index, device := range devices {
+			if device.DeviceType == p.DeviceType {
+				return index, nil
+			}
+		}
+		return -1, fmt.Errorf("ScriptedPrompter: no device of type '%s'", p.DeviceType)
+	}
+	if p.DeviceIndex < 0 || p.DeviceIndex >= len(devices) {
+		return -1, fmt.Errorf("ScriptedPrompter: invalid index %d. It must be between 0 and %d", p.DeviceIndex, len(devices)-1)
+	}
+	return p.DeviceIndex, nil
+}
+
+// GetOTP returns the next scripted OTP code.
+func (p *ScriptedPrompter) GetOTP(device api.SAMLAssertionDevice) (otp string, err error) {
+	if len(p.OTPs) == 0 {
+		return "", fmt.Errorf("ScriptedPrompter: no OTP code left for device %d (%s)", device.DeviceID, device.DeviceType)
+	}
+	otp = p.OTPs[0]
+	p.OTPs = p.OTPs[1:]
+	return
+}
+
+// PushPending counts the push notifications pending.
+func (p *ScriptedPrompter) PushPending(device api.SAMLAssertionDevice) {
+	p.PushCount++
+}
+
+// Progress records the message.
+func (p *ScriptedPrompter) Progress(message string) {
+	p.Messages = append(p.Messages, message)
+}
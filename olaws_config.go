@@ -0,0 +1,339 @@
+package onelogin
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clarsonneur/onelogin/common"
+	"github.com/op/go-logging"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Environment variables overriding the OL-AWS configuration file.
+const (
+	OLAWSConfigEnv           = "OL_AWS_CONFIG"
+	OneLoginShardEnv         = "ONELOGIN_SHARD"
+	OneLoginSubdomainEnv     = "ONELOGIN_SUBDOMAIN"
+	OneLoginClientIDEnv      = "ONELOGIN_CLIENT_ID"
+	OneLoginClientSecretEnv  = "ONELOGIN_CLIENT_SECRET"
+	OneLoginUsernameEnv      = "ONELOGIN_USERNAME"
+	OneLoginAppIDEnv         = "ONELOGIN_APP_ID"
+	OneLoginMFADeviceTypeEnv = "ONELOGIN_MFA_DEVICE_TYPE"
+	OneLoginTOTPEnv          = "ONELOGIN_TOTP"
+)
+
+// AWS STS limits of the session duration.
+const (
+	MinAwsSessionDuration = 15 * time.Minute
+	MaxAwsSessionDuration = 12 * time.Hour
+)
+
+// OLAWSConfig is the OL-AWS configuration file (~/.ol-aws.yml) shared by tools built on this library.
+//
+//	shard: eu
+//	subdomain: myCompany
+//	client_id: 0123456789abcdef
+//	client_secret: env:ONELOGIN_SECRET
+//	username: me@myCompany.com
+//	app_id: "123456"
+//	mfa_device_type: OneLogin Protect
+//	profiles:
+//	  prod:
+//	    role_arn: arn:aws:iam::123456789012:role/Admin
+//	    duration: 1h
+//	    region: eu-west-1
+type OLAWSConfig struct {
+	Shard     string `yaml:"shard"`
+	Subdomain string `yaml:"subdomain"`
+	ClientID  string `yaml:"client_id"`
+	// ClientSecret is a reference to the client secret. See ResolveSecret.
+	ClientSecret string `yaml:"client_secret"`
+	Username     string `yaml:"username,omitempty"`
+	// AppID is the default OneLogin AWS app ID, used by profiles without app_id.
+	AppID string `yaml:"app_id,omitempty"`
+	// MFADeviceType is the preferred MFA device type. Ex: "OneLogin Protect"
+	MFADeviceType string `yaml:"mfa_device_type,omitempty"`
+	// TOTP is a reference to the TOTP seed of the user MFA device (base32 secret or otpauth:// URI), for
+	// authentication without user interaction. See ResolveSecret for the reference forms.
+	TOTP     string                   `yaml:"totp,omitempty"`
+	Profiles map[string]*OLAWSProfile `yaml:"profiles,omitempty"`
+
+	// overrides are the values set by ApplyEnv, by environment variable, so that Save writes the file values.
+	overrides map[string]envOverride
+}
+
+// envOverride is a configuration value overridden by an environment variable.
+type envOverride struct {
+	file string
+	env  string
+}
+
+// OLAWSProfile is an AWS profile of the OL-AWS configuration.
+type OLAWSProfile struct {
+	// AppID is the OneLogin AWS app ID. The configuration AppID if empty.
+	AppID string `yaml:"app_id,omitempty"`
+	// RoleARN is the role to assume. See AwsRoleSelector for the forms accepted.
+	RoleARN string `yaml:"role_arn,omitempty"`
+	// Duration is the session duration. If 0, the assertion session duration is used.
+	Duration time.Duration `yaml:"duration,omitempty"`
+	Region   string        `yaml:"region,omitempty"`
+	Output   string        `yaml:"output,omitempty"`
+}
+
+// NewOLAWSConfig creates an empty OLAWSConfig.
+func NewOLAWSConfig() (ret *OLAWSConfig) {
+	ret = new(OLAWSConfig)
+	ret.Profiles = make(map[string]*OLAWSProfile)
+	return
+}
+
+// OLAWSConfigPath return the OL-AWS configuration file path: $OL_AWS_CONFIG or ~/.ol-aws.yml
+func OLAWSConfigPath() string {
+	if path := os.Getenv(OLAWSConfigEnv); path != "" {
+		return path
+	}
+	return common.DefaultOLAWSConfigPath()
+}
+
+// LoadOLAWSConfig reads the OL-AWS configuration file, applies the environment variables overrides and validates it.
+// If path is empty, OLAWSConfigPath() is used. A missing file is not an error, when the environment gives the
+// configuration.
+func LoadOLAWSConfig(path string) (ret *OLAWSConfig, err error) {
+	if path == "" {
+		path = OLAWSConfigPath()
+	}
+
+	ret = NewOLAWSConfig()
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err = yaml.UnmarshalStrict(data, ret); err != nil {
+			return nil, fmt.Errorf("Unable to read %s: %s", path, err)
+		}
+	}
+	if ret.Profiles == nil {
+		ret.Profiles = make(map[string]*OLAWSProfile)
+	}
+
+	ret.ApplyEnv()
+	if err = ret.Validate(); err != nil {
+		return nil, fmt.Errorf("Invalid configuration %s: %s", path, err)
+	}
+	return ret, nil
+}
+
+// ApplyEnv overrides the configuration with the ONELOGIN_* environment variables which are set.
+// The values overridden are not written by Save.
+func (c *OLAWSConfig) ApplyEnv() {
+	if c == nil {
+		return
+	}
+	for env, value := range c.envFields() {
+		v, found := os.LookupEnv(env)
+		if !found || v == "" {
+			continue
+		}
+		if c.overrides == nil {
+			c.overrides = make(map[string]envOverride)
+		}
+		override, overridden := c.overrides[env]
+		if !overridden || *value != override.env {
+			override.file = *value
+		}
+		override.env = v
+		c.overrides[env] = override
+		*value = v
+	}
+}
+
+// envFields return the configuration fields by environment variable.
+func (c *OLAWSConfig) envFields() map[string]*string {
+	return map[string]*string{
+		OneLoginShardEnv:         &c.Shard,
+		OneLoginSubdomainEnv:     &c.Subdomain,
+		OneLoginClientIDEnv:      &c.ClientID,
+		OneLoginClientSecretEnv:  &c.ClientSecret,
+		OneLoginUsernameEnv:      &c.Username,
+		OneLoginAppIDEnv:         &c.AppID,
+		OneLoginMFADeviceTypeEnv: &c.MFADeviceType,
+		OneLoginTOTPEnv:          &c.TOTP,
+	}
+}
+
+// Validate return an error listing the configuration issues found.
+func (c *OLAWSConfig) Validate() error {
+	if c == nil {
+		return errors.New("OLAWSConfig is nil")
+	}
+
+	var issues []string
+	for key, value := range map[string]string{
+		"shard":         c.Shard,
+		"subdomain":     c.Subdomain,
+		"client_id":     c.ClientID,
+		"client_secret": c.ClientSecret,
+	} {
+		if value == "" {
+			issues = append(issues, key+" is required")
+		}
+	}
+
+	for name, profile := range c.Profiles {
+		if profile == nil {
+			issues = append(issues, fmt.Sprintf("profile %s is empty", name))
+			continue
+		}
+		if profile.AppID == "" && c.AppID == "" {
+			issues = append(issues, fmt.Sprintf("profile %s: app_id is required (no default app_id)", name))
+		}
+		if profile.Duration != 0 && (profile.Duration < MinAwsSessionDuration || profile.Duration > MaxAwsSessionDuration) {
+			issues = append(issues, fmt.Sprintf("profile %s: duration %s must be between %s and %s", name,
+				profile.Duration, MinAwsSessionDuration, MaxAwsSessionDuration))
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	sort.Strings(issues)
+	return errors.New(strings.Join(issues, ", "))
+}
+
+// Profile return the profile named, with the configuration defaults applied.
+func (c *OLAWSConfig) Profile(name string) (ret *OLAWSProfile, err error) {
+	if c == nil {
+		return nil, errors.New("OLAWSConfig is nil")
+	}
+	profile, found := c.Profiles[name]
+	if !found || profile == nil {
+		return nil, fmt.Errorf("Profile '%s' not found in the configuration. Available: %s", name,
+			strings.Join(c.ProfileNames(), ", "))
+	}
+
+	ret = new(OLAWSProfile)
+	*ret = *profile
+	if ret.AppID == "" {
+		ret.AppID = c.AppID
+	}
+	return
+}
+
+// ProfileNames return the sorted list of profile names.
+func (c *OLAWSConfig) ProfileNames() (ret []string) {
+	if c == nil {
+		return
+	}
+	ret = make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return
+}
+
+// ResolveSecret return the client secret from its reference:
+//   - "env:VAR" reads the environment variable VAR,
+//   - "file:PATH" reads the file PATH (a leading ~/ is the user home directory),
+//   - otherwise, the value is the secret itself.
+func (c *OLAWSConfig) ResolveSecret() (ret string, err error) {
+	if c == nil {
+		return "", errors.New("OLAWSConfig is nil")
+	}
+	return resolveReference("client secret", c.ClientSecret)
+}
+
+// ResolveTOTP return the TOTP generator of the TOTP reference. nil if there is no TOTP configured.
+func (c *OLAWSConfig) ResolveTOTP() (ret *TOTP, err error) {
+	if c == nil || c.TOTP == "" {
+		return
+	}
+	seed, err := resolveReference("TOTP seed", c.TOTP)
+	if err != nil {
+		return
+	}
+	if strings.HasPrefix(seed, "otpauth://") {
+		return ParseTOTPURI(seed)
+	}
+	return NewTOTP(seed)
+}
+
+// resolveReference return the value of a reference: "env:VAR", "file:PATH" or the value itself.
+func resolveReference(name, ref string) (ret string, err error) {
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		env := strings.TrimPrefix(ref, "env:")
+		ret = os.Getenv(env)
+		if ret == "" {
+			err = fmt.Errorf("%s environment variable %s is not set", name, env)
+		}
+	case strings.HasPrefix(ref, "file:"):
+		path := strings.TrimPrefix(ref, "file:")
+		if strings.HasPrefix(path, "~/") {
+			path = common.UserHomeDir() + path[1:]
+		}
+		var data []byte
+		if data, err = ioutil.ReadFile(path); err != nil {
+			return
+		}
+		ret = strings.TrimSpace(string(data))
+	default:
+		ret = ref
+	}
+	return
+}
+
+// NewService creates the Service connected to OneLogin as configured.
+// If a TOTP is configured, it is set as the Service OTPSource. If a MFA device type is configured, the device is
+// selected by type (see MFADevicePolicy).
+func (c *OLAWSConfig) NewService(loglevel logging.Level) (ret *Service, err error) {
+	if err = c.Validate(); err != nil {
+		return
+	}
+	secret, err := c.ResolveSecret()
+	if err != nil {
+		return
+	}
+	totp, err := c.ResolveTOTP()
+	if err != nil {
+		return
+	}
+	ret = NewService(c.Shard, c.ClientID, secret, c.Subdomain, loglevel)
+	if totp != nil {
+		ret.SetOTPSource(NewTOTPSource(totp))
+	}
+	if c.MFADeviceType != "" {
+		ret.SetMFADevicePolicy(NewMFADevicePolicyByType(c.MFADeviceType))
+	}
+	return
+}
+
+// Save writes the configuration to the file given. If path is empty, OLAWSConfigPath() is used.
+// The file is replaced atomically, with 0600 permissions as it may contain the client secret.
+// Values overridden by environment variables (see ApplyEnv) are saved with their file value, unless they have been
+// changed since.
+func (c *OLAWSConfig) Save(path string) (err error) {
+	if c == nil {
+		return errors.New("OLAWSConfig is nil")
+	}
+	if path == "" {
+		path = OLAWSConfigPath()
+	}
+	file := *c
+	fields := file.envFields()
+	for env, override := range c.overrides {
+		if value := fields[env]; *value == override.env {
+			*value = override.file
+		}
+	}
+	data, err := yaml.Marshal(&file)
+	if err != nil {
+		return
+	}
+	return common.WriteFileAtomic(path, data, 0600)
+}
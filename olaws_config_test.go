@@ -0,0 +1,87 @@
+package onelogin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setEnv sets the environment variables, and return a function restoring them.
+func setEnv(values map[string]string) (restore func()) {
+	previous := make(map[string]*string)
+	for env, value := range values {
+		if v, found := os.LookupEnv(env); found {
+			previous[env] = &v
+		} else {
+			previous[env] = nil
+		}
+		os.Setenv(env, value)
+	}
+	return func() {
+		for env, value := range previous {
+			if value == nil {
+				os.Unsetenv(env)
+			} else {
+				os.Setenv(env, *value)
+			}
+		}
+	}
+}
+
+func TestOLAWSConfigSaveKeepsFileValues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ol-aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ol-aws.yml")
+
+	content := "shard: eu\nsubdomain: myCompany\nclient_id: id\nclient_secret: env:MY_SECRET\n" +
+		"totp: file:~/.ol-aws.totp\napp_id: \"123\"\n"
+	if err = ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer setEnv(map[string]string{
+		OneLoginClientSecretEnv: "plain-secret",
+		OneLoginTOTPEnv:         "JBSWY3DPEHPK3PXP",
+		OneLoginAppIDEnv:        "456",
+		OneLoginShardEnv:        "",
+	})()
+
+	config, err := LoadOLAWSConfig(path)
+	if err != nil {
+		t.Fatalf("LoadOLAWSConfig: %s", err)
+	}
+	if config.ClientSecret != "plain-secret" || config.TOTP != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("environment not applied: %+v", config)
+	}
+
+	// A value changed after loading is saved.
+	config.AppID = "789"
+	config.Profiles["prod"] = &OLAWSProfile{RoleARN: "arn:aws:iam::123456789012:role/Admin"}
+	if err = config.Save(path); err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+	if config.ClientSecret != "plain-secret" {
+		t.Errorf("Save changed the configuration: %+v", config)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := string(data)
+	for _, want := range []string{"client_secret: env:MY_SECRET", "totp: file:~/.ol-aws.totp", "app_id: \"789\"",
+		"role_arn: arn:aws:iam::123456789012:role/Admin"} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("saved configuration has no '%s':\n%s", want, saved)
+		}
+	}
+	for _, secret := range []string{"plain-secret", "JBSWY3DPEHPK3PXP"} {
+		if strings.Contains(saved, secret) {
+			t.Errorf("saved configuration has the environment value '%s':\n%s", secret, saved)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package onelogin
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+// Push notification errors. Use errors.Is to check them.
+var (
+	ErrPushDenied   = errors.New("onelogin: push notification denied")
+	ErrPushTimedOut = errors.New("onelogin: push notification timed out")
+)
+
+// PushResult is the state of a push notification sent to a MFA device.
+type PushResult int
+
+// Push notification states.
+const (
+	PushPending PushResult = iota
+	PushApproved
+	PushDenied
+	PushTimedOut
+)
+
+// String return the push notification state name.
+func (r PushResult) String() string {
+	switch r {
+	case PushPending:
+		return "pending"
+	case PushApproved:
+		return "approved"
+	case PushDenied:
+		return "denied"
+	case PushTimedOut:
+		return "timed out"
+	}
+	return "unknown"
+}
+
+// PushProgress reports the progress of a push notification approval.
+type PushProgress struct {
+	Device api.SAMLAssertionDevice
+	// Attempt is the number of times OneLogin has been polled.
+	Attempt int
+	Elapsed time.Duration
+	Result  PushResult
+}
+
+// PushPolicy define how Service.SAMLAuthenticateOTP waits for a push notification approval.
+// OneLogin is polled every Interval, increased by Backoff after each poll up to MaxInterval, until the
+// notification is approved, denied or Timeout is reached.
+// Zero Interval, Backoff, MaxInterval and Timeout are replaced by the NewPushPolicy values.
+type PushPolicy struct {
+	Interval    time.Duration
+	Backoff     float64
+	MaxInterval time.Duration
+	Timeout     time.Duration
+	// FallbackToOTP asks the OTP code of the device when the push notification timed out.
+	FallbackToOTP bool
+	// OnProgress is called after each poll and with the final result. Optional.
+	OnProgress func(progress PushProgress)
+}
+
+// NewPushPolicy return a PushPolicy with default values: polled from every 3s up to every 15s, for 2 minutes,
+// then falling back to OTP.
+func NewPushPolicy() (ret *PushPolicy) {
+	ret = new(PushPolicy)
+	ret.Interval = 3 * time.Second
+	ret.Backoff = 1.5
+	ret.MaxInterval = 15 * time.Second
+	ret.Timeout = 2 * time.Minute
+	ret.FallbackToOTP = true
+	return
+}
+
+// withDefaults return a copy of the policy, with the NewPushPolicy values for the zero fields.
+func (p *PushPolicy) withDefaults() (ret *PushPolicy) {
+	ret = new(PushPolicy)
+	*ret = *p
+	defaults := NewPushPolicy()
+	if ret.Interval <= 0 {
+		ret.Interval = defaults.Interval
+	}
+	if ret.Backoff == 0 {
+		ret.Backoff = defaults.Backoff
+	}
+	if ret.MaxInterval <= 0 {
+		ret.MaxInterval = defaults.MaxInterval
+	}
+	if ret.Timeout <= 0 {
+		ret.Timeout = defaults.Timeout
+	}
+	return
+}
+
+// nextInterval return the interval following the one given.
+func (p *PushPolicy) nextInterval(interval time.Duration) time.Duration {
+	if p.Backoff > 1 {
+		interval = time.Duration(float64(interval) * p.Backoff)
+	}
+	if p.MaxInterval > 0 && interval > p.MaxInterval {
+		interval = p.MaxInterval
+	}
+	return interval
+}
+
+// progress calls OnProgress, if set.
+func (p *PushPolicy) progress(device api.SAMLAssertionDevice, attempt int, start time.Time, result PushResult) {
+	if p.OnProgress == nil {
+		return
+	}
+	p.OnProgress(PushProgress{Device: device, Attempt: attempt, Elapsed: time.Since(start), Result: result})
+}
+
+// isPushDenied return true if OneLogin reports that the user denied the push notification.
+// While polling, OneLogin answers "pending" with a 200 status until the notification is approved or denied. A
+// denied notification is answered with a 401 status. As no OTP code is sent when polling, a 401 cannot be an
+// invalid code, and it cannot be an expired or revoked token either: the poll renews a rejected token and is retried
+// once before the 401 is returned (see api.VerifyFactorResult.PostWithContext).
+func isPushDenied(httpStatus int, status api.ResultStatus) bool {
+	return httpStatus == http.StatusUnauthorized || status.Code == http.StatusUnauthorized
+}
@@ -0,0 +1,145 @@
+package onelogin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+var testProtectDevice = api.SAMLAssertionDevice{DeviceID: 1, DeviceType: DeviceTypeOneLoginProtect}
+
+// testPushPolicy return a fast PushPolicy.
+func testPushPolicy(fallbackToOTP bool) *PushPolicy {
+	return &PushPolicy{Interval: time.Millisecond, Backoff: 1, MaxInterval: time.Millisecond,
+		Timeout: 50 * time.Millisecond, FallbackToOTP: fallbackToOTP}
+}
+
+func writePending(w http.ResponseWriter) {
+	fmt.Fprint(w, `{"status":{"error":false,"code":200,"type":"pending","message":"Authentication pending on OL Protect"}}`)
+}
+
+func TestPushPolicyWithDefaults(t *testing.T) {
+	policy := (&PushPolicy{Timeout: time.Minute, FallbackToOTP: false}).withDefaults()
+	defaults := NewPushPolicy()
+	if policy.Interval != defaults.Interval || policy.Backoff != defaults.Backoff ||
+		policy.MaxInterval != defaults.MaxInterval || policy.Timeout != time.Minute || policy.FallbackToOTP {
+		t.Errorf("policy = %+v", policy)
+	}
+}
+
+func TestPushApproved(t *testing.T) {
+	stub := newMFAStub(t, func(w http.ResponseWriter, request api.VerifyFactorRequest, call int) {
+		if call < 3 {
+			writePending(w)
+			return
+		}
+		writeVerified(w)
+	}, testProtectDevice)
+	defer stub.Close()
+
+	var results []PushResult
+	policy := testPushPolicy(false)
+	policy.OnProgress = func(progress PushProgress) { results = append(results, progress.Result) }
+	service := stub.service(NewScriptedPrompter(0))
+	service.SetPushPolicy(policy)
+
+	assertion, err := service.SAMLAuthenticateOTP("me", "password", "1", "", "", -1)
+	if err != nil {
+		t.Fatalf("SAMLAuthenticateOTP: %s", err)
+	}
+	if assertion.MfaVerifyInfo.PushResult != PushApproved {
+		t.Errorf("push result = %s, want approved", assertion.MfaVerifyInfo.PushResult)
+	}
+	if fmt.Sprint(results) != "[pending approved]" {
+		t.Errorf("progress = %v, want [pending approved]", results)
+	}
+	if requests := stub.requests(); len(requests) != 3 || requests[0].DoNotNotify || !requests[1].DoNotNotify {
+		t.Errorf("verify_factor requests = %+v", requests)
+	}
+}
+
+func TestPushDenied(t *testing.T) {
+	stub := newMFAStub(t, func(w http.ResponseWriter, request api.VerifyFactorRequest, call int) {
+		if call < 2 {
+			writePending(w)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"status":{"error":true,"code":401,"type":"Unauthorized","message":"Authentication Failed"}}`)
+	}, testProtectDevice)
+	defer stub.Close()
+
+	service := stub.service(NewScriptedPrompter(0, "123456"))
+	service.SetPushPolicy(testPushPolicy(true))
+	_, err := service.SAMLAuthenticateOTP("me", "password", "1", "", "", -1)
+	if !errors.Is(err, ErrPushDenied) {
+		t.Errorf("err = %v, want ErrPushDenied", err)
+	}
+	// The denied poll is retried once with a new token, but the OTP is not asked.
+	if requests := stub.requests(); len(requests) != 3 || stub.tokens != 2 {
+		t.Errorf("verify_factor requests = %+v, tokens = %d, want no OTP fallback", requests, stub.tokens)
+	}
+}
+
+func TestPushExpiredTokenNotDenied(t *testing.T) {
+	stub := newMFAStub(t, func(w http.ResponseWriter, request api.VerifyFactorRequest, call int) {
+		if call < 3 {
+			writePending(w)
+			return
+		}
+		writeVerified(w)
+	}, testProtectDevice)
+	defer stub.Close()
+
+	service := stub.service(NewScriptedPrompter(0))
+	service.SetPushPolicy(testPushPolicy(false))
+	// The token expires or is revoked while polling.
+	policy := service.getPushPolicy()
+	policy.OnProgress = func(progress PushProgress) {
+		stub.lock.Lock()
+		stub.revoked = "token-1"
+		stub.lock.Unlock()
+	}
+
+	assertion, err := service.SAMLAuthenticateOTP("me", "password", "1", "", "", -1)
+	if err != nil {
+		t.Fatalf("SAMLAuthenticateOTP: %s", err)
+	}
+	if assertion.MfaVerifyInfo.PushResult != PushApproved {
+		t.Errorf("push result = %s, want approved", assertion.MfaVerifyInfo.PushResult)
+	}
+	if stub.tokens != 2 {
+		t.Errorf("tokens delivered = %d, want 2", stub.tokens)
+	}
+}
+
+func TestPushTimedOut(t *testing.T) {
+	stub := newMFAStub(t, func(w http.ResponseWriter, request api.VerifyFactorRequest, call int) {
+		if request.OTPToken == "123456" {
+			writeVerified(w)
+			return
+		}
+		writePending(w)
+	}, testProtectDevice)
+	defer stub.Close()
+
+	service := stub.service(NewScriptedPrompter(0, "123456"))
+	service.SetPushPolicy(testPushPolicy(false))
+	if _, err := service.SAMLAuthenticateOTP("me", "password", "1", "", "", -1); !errors.Is(err, ErrPushTimedOut) {
+		t.Errorf("err = %v, want ErrPushTimedOut", err)
+	}
+
+	// With the OTP fallback, the code is asked and verified.
+	service.SetPushPolicy(testPushPolicy(true))
+	assertion, err := service.SAMLAuthenticateOTP("me", "password", "1", "", "", -1)
+	if err != nil {
+		t.Fatalf("SAMLAuthenticateOTP: %s", err)
+	}
+	if assertion.MfaVerifyInfo.OTPToken != "123456" {
+		t.Errorf("OTP = %s, want 123456", assertion.MfaVerifyInfo.OTPToken)
+	}
+}
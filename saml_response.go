@@ -0,0 +1,193 @@
+package onelogin
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AWS SAML attributes
+// See https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_providers_create_saml_assertions.html
+const (
+	AwsRoleAttribute            = "https://aws.amazon.com/SAML/Attributes/Role"
+	AwsRoleSessionNameAttribute = "https://aws.amazon.com/SAML/Attributes/RoleSessionName"
+	AwsSessionDurationAttribute = "https://aws.amazon.com/SAML/Attributes/SessionDuration"
+)
+
+var (
+	// ErrEncryptedAssertion is returned when the SAML response contains an encrypted assertion, which cannot be parsed.
+	ErrEncryptedAssertion = errors.New("SAML assertion is encrypted")
+	// ErrNoAssertion is returned when the SAML response has no assertion.
+	ErrNoAssertion = errors.New("SAML response has no assertion")
+)
+
+// SAMLAssertionData is the structured data of a SAML response assertion.
+type SAMLAssertionData struct {
+	Issuer       string
+	NameID       string
+	NameIDFormat string
+	// NotBefore and NotOnOrAfter are the assertion validity conditions.
+	NotBefore    time.Time
+	NotOnOrAfter time.Time
+	Audiences    []string
+	// Attributes are the assertion attribute values, by attribute name.
+	Attributes map[string][]string
+
+	// AwsRoles are the roles given by the AWS Role attribute.
+	AwsRoles []AwsRole
+	// SessionDuration is given by the AWS SessionDuration attribute. 0 if not set.
+	SessionDuration time.Duration
+}
+
+// AwsRole is an AWS role which can be assumed with the SAML assertion.
+type AwsRole struct {
+	RoleARN      string
+	PrincipalARN string
+}
+
+// AccountID return the AWS account ID of the role. Ex: "123456789012" for "arn:aws:iam::123456789012:role/Admin"
+func (r AwsRole) AccountID() string {
+	fields := strings.SplitN(r.RoleARN, ":", 6)
+	if len(fields) < 6 {
+		return ""
+	}
+	return fields[4]
+}
+
+// RoleName return the name of the role. Ex: "Admin" for "arn:aws:iam::123456789012:role/Admin"
+func (r AwsRole) RoleName() string {
+	return r.RoleARN[strings.LastIndex(r.RoleARN, "/")+1:]
+}
+
+// samlResponse is the xml structure of a SAML response. Namespaces are ignored.
+type samlResponse struct {
+	XMLName            xml.Name
+	Issuer             string         `xml:"Issuer"`
+	Assertion          *samlAssertion `xml:"Assertion"`
+	EncryptedAssertion *struct{}      `xml:"EncryptedAssertion"`
+}
+
+type samlAssertion struct {
+	Issuer  string `xml:"Issuer"`
+	Subject struct {
+		NameID struct {
+			Format string `xml:"Format,attr"`
+			Value  string `xml:",chardata"`
+		} `xml:"NameID"`
+	} `xml:"Subject"`
+	Conditions struct {
+		NotBefore    string   `xml:"NotBefore,attr"`
+		NotOnOrAfter string   `xml:"NotOnOrAfter,attr"`
+		Audiences    []string `xml:"AudienceRestriction>Audience"`
+	} `xml:"Conditions"`
+	Attributes []struct {
+		Name   string   `xml:"Name,attr"`
+		Values []string `xml:"AttributeValue"`
+	} `xml:"AttributeStatement>Attribute"`
+}
+
+// ParseSAMLResponse parses a decoded SAML response (xml).
+// It fails with ErrEncryptedAssertion if the assertion is encrypted.
+func ParseSAMLResponse(data []byte) (ret *SAMLAssertionData, err error) {
+	response := samlResponse{}
+	if err = xml.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("Malformed SAML response: %w", err)
+	}
+	if response.XMLName.Local != "Response" {
+		return nil, fmt.Errorf("Malformed SAML response: unexpected root element '%s'", response.XMLName.Local)
+	}
+	if response.Assertion == nil {
+		if response.EncryptedAssertion != nil {
+			return nil, ErrEncryptedAssertion
+		}
+		return nil, ErrNoAssertion
+	}
+	assertion := response.Assertion
+
+	ret = new(SAMLAssertionData)
+	ret.Issuer = strings.TrimSpace(assertion.Issuer)
+	if ret.Issuer == "" {
+		ret.Issuer = strings.TrimSpace(response.Issuer)
+	}
+	ret.NameID = strings.TrimSpace(assertion.Subject.NameID.Value)
+	ret.NameIDFormat = assertion.Subject.NameID.Format
+	if ret.NotBefore, err = parseSAMLTime(assertion.Conditions.NotBefore); err != nil {
+		return nil, err
+	}
+	if ret.NotOnOrAfter, err = parseSAMLTime(assertion.Conditions.NotOnOrAfter); err != nil {
+		return nil, err
+	}
+	for _, audience := range assertion.Conditions.Audiences {
+		ret.Audiences = append(ret.Audiences, strings.TrimSpace(audience))
+	}
+
+	ret.Attributes = make(map[string][]string)
+	for _, attribute := range assertion.Attributes {
+		for _, value := range attribute.Values {
+			ret.Attributes[attribute.Name] = append(ret.Attributes[attribute.Name], strings.TrimSpace(value))
+		}
+	}
+
+	for _, value := range ret.Attributes[AwsRoleAttribute] {
+		var role AwsRole
+		if role, err = parseAwsRole(value); err != nil {
+			return nil, err
+		}
+		ret.AwsRoles = append(ret.AwsRoles, role)
+	}
+
+	if values := ret.Attributes[AwsSessionDurationAttribute]; len(values) > 0 {
+		seconds, errConv := strconv.Atoi(values[0])
+		if errConv != nil {
+			return nil, fmt.Errorf("Malformed SAML response: invalid AWS SessionDuration '%s'", values[0])
+		}
+		ret.SessionDuration = time.Duration(seconds) * time.Second
+	}
+	return
+}
+
+// Parse parses the decoded SAML response of the assertion. See ParseSAMLResponse.
+func (a *AwsSAMLAssertion) Parse() (*SAMLAssertionData, error) {
+	if a == nil {
+		return nil, errors.New("Parse: AwsSAMLAssertion object is nil")
+	}
+	if len(a.SamlResponse) == 0 {
+		return nil, errors.New("Parse: no SAML response")
+	}
+	return ParseSAMLResponse(a.SamlResponse)
+}
+
+// parseAwsRole parses the AWS Role attribute value "<role ARN>,<principal ARN>". Both orders are accepted.
+func parseAwsRole(value string) (ret AwsRole, err error) {
+	fields := strings.Split(value, ",")
+	if len(fields) != 2 {
+		return ret, fmt.Errorf("Malformed SAML response: invalid AWS Role '%s'", value)
+	}
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		switch {
+		case strings.Contains(field, ":saml-provider/"):
+			ret.PrincipalARN = field
+		case strings.Contains(field, ":role/"):
+			ret.RoleARN = field
+		}
+	}
+	if ret.RoleARN == "" || ret.PrincipalARN == "" {
+		return ret, fmt.Errorf("Malformed SAML response: invalid AWS Role '%s'", value)
+	}
+	return
+}
+
+// parseSAMLTime parses a SAML time attribute. An empty value returns a zero time.
+func parseSAMLTime(value string) (ret time.Time, err error) {
+	if value == "" {
+		return
+	}
+	if ret, err = time.Parse(time.RFC3339, value); err != nil {
+		err = fmt.Errorf("Malformed SAML response: invalid time '%s'", value)
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package onelogin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSAMLResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">
+  <saml:Issuer>https://app.onelogin.com/saml/metadata/123456</saml:Issuer>
+  <saml:Assertion>
+    <saml:Issuer>https://app.onelogin.com/saml/metadata/123456</saml:Issuer>
+    <saml:Subject>
+      <saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">me@myCompany.com</saml:NameID>
+    </saml:Subject>
+    <saml:Conditions NotBefore="2020-01-02T10:00:00Z" NotOnOrAfter="2020-01-02T10:03:00Z">
+      <saml:AudienceRestriction>
+        <saml:Audience>urn:amazon:webservices</saml:Audience>
+      </saml:AudienceRestriction>
+    </saml:Conditions>
+    <saml:AttributeStatement>
+      <saml:Attribute Name="https://aws.amazon.com/SAML/Attributes/Role">
+        <saml:AttributeValue>arn:aws:iam::123456789012:role/Admin,arn:aws:iam::123456789012:saml-provider/OneLogin</saml:AttributeValue>
+        <saml:AttributeValue>arn:aws:iam::210987654321:saml-provider/OneLogin,arn:aws:iam::210987654321:role/ReadOnly</saml:AttributeValue>
+      </saml:Attribute>
+      <saml:Attribute Name="https://aws.amazon.com/SAML/Attributes/RoleSessionName">
+        <saml:AttributeValue>me@myCompany.com</saml:AttributeValue>
+      </saml:Attribute>
+      <saml:Attribute Name="https://aws.amazon.com/SAML/Attributes/SessionDuration">
+        <saml:AttributeValue>3600</saml:AttributeValue>
+      </saml:Attribute>
+    </saml:AttributeStatement>
+  </saml:Assertion>
+</samlp:Response>`
+
+func TestParseSAMLResponse(t *testing.T) {
+	data, err := ParseSAMLResponse([]byte(testSAMLResponse))
+	if err != nil {
+		t.Fatalf("ParseSAMLResponse: %s", err)
+	}
+	if data.Issuer != "https://app.onelogin.com/saml/metadata/123456" {
+		t.Errorf("Issuer = %s", data.Issuer)
+	}
+	if data.NameID != "me@myCompany.com" || !strings.HasSuffix(data.NameIDFormat, "emailAddress") {
+		t.Errorf("NameID = %s, format %s", data.NameID, data.NameIDFormat)
+	}
+	if !data.NotBefore.Equal(time.Date(2020, 1, 2, 10, 0, 0, 0, time.UTC)) ||
+		!data.NotOnOrAfter.Equal(time.Date(2020, 1, 2, 10, 3, 0, 0, time.UTC)) {
+		t.Errorf("conditions = %s - %s", data.NotBefore, data.NotOnOrAfter)
+	}
+	if len(data.Audiences) != 1 || data.Audiences[0] != "urn:amazon:webservices" {
+		t.Errorf("Audiences = %v", data.Audiences)
+	}
+	if data.SessionDuration != time.Hour {
+		t.Errorf("SessionDuration = %s, want 1h", data.SessionDuration)
+	}
+	if got := data.Attributes[AwsRoleSessionNameAttribute]; len(got) != 1 || got[0] != "me@myCompany.com" {
+		t.Errorf("RoleSessionName = %v", got)
+	}
+
+	want := []AwsRole{
+		{RoleARN: "arn:aws:iam::123456789012:role/Admin", PrincipalARN: "arn:aws:iam::123456789012:saml-provider/OneLogin"},
+		{RoleARN: "arn:aws:iam::210987654321:role/ReadOnly", PrincipalARN: "arn:aws:iam::210987654321:saml-provider/OneLogin"},
+	}
+	if len(data.AwsRoles) != len(want) {
+		t.Fatalf("AwsRoles = %+v", data.AwsRoles)
+	}
+	for index, role := range want {
+		if data.AwsRoles[index] != role {
+			t.Errorf("AwsRoles[%d] = %+v, want %+v", index, data.AwsRoles[index], role)
+		}
+	}
+	if data.AwsRoles[1].AccountID() != "210987654321" || data.AwsRoles[1].RoleName() != "ReadOnly" {
+		t.Errorf("AccountID = %s, RoleName = %s", data.AwsRoles[1].AccountID(), data.AwsRoles[1].RoleName())
+	}
+}
+
+func TestParseSAMLResponseMalformed(t *testing.T) {
+	for name, response := range map[string]string{
+		"not xml":      "not a SAML response",
+		"truncated":    testSAMLResponse[:len(testSAMLResponse)/2],
+		"root element": `<Assertion></Assertion>`,
+		"aws role": strings.Replace(testSAMLResponse,
+			",arn:aws:iam::123456789012:saml-provider/OneLogin", "", 1),
+		"session duration": strings.Replace(testSAMLResponse, ">3600<", ">1h<", 1),
+		"time":             strings.Replace(testSAMLResponse, "2020-01-02T10:00:00Z", "yesterday", 1),
+	} {
+		if _, err := ParseSAMLResponse([]byte(response)); err == nil {
+			t.Errorf("%s: no error", name)
+		} else if !strings.HasPrefix(err.Error(), "Malformed SAML response") {
+			t.Errorf("%s: err = %s", name, err)
+		}
+	}
+}
+
+func TestParseSAMLResponseWithoutAssertion(t *testing.T) {
+	encrypted := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">
+  <saml:EncryptedAssertion><xenc:EncryptedData xmlns:xenc="http://www.w3.org/2001/04/xmlenc#"/></saml:EncryptedAssertion>
+</samlp:Response>`
+	if _, err := ParseSAMLResponse([]byte(encrypted)); !errors.Is(err, ErrEncryptedAssertion) {
+		t.Errorf("err = %v, want ErrEncryptedAssertion", err)
+	}
+
+	empty := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol"></samlp:Response>`
+	if _, err := ParseSAMLResponse([]byte(empty)); !errors.Is(err, ErrNoAssertion) {
+		t.Errorf("err = %v, want ErrNoAssertion", err)
+	}
+}
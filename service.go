@@ -1,9 +1,13 @@
 package onelogin
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/clarsonneur/onelogin/api"
@@ -13,18 +17,48 @@ import (
 
 // OneLoginURL is the default OneLogin API endpoint
 const (
-	OneLoginURL                = "https://api.%s.onelogin.com"
+	OneLoginURL = "https://api.%s.onelogin.com"
+	// TimeSleepOnResponsePending is the former push notification poll interval, in seconds.
+	//
+	// Deprecated: use PushPolicy.
 	TimeSleepOnResponsePending = 15
-	MaxIterGetSAMLResponse     = 6
+	// MaxIterGetSAMLResponse is the former number of push notification polls.
+	//
+	// Deprecated: use PushPolicy.
+	MaxIterGetSAMLResponse = 6
 )
 
 // Service is the core OneLogin service object, connected to the OneLogin Service through the API (api.Core).
 type Service struct {
-	core *api.Core
+	core      *api.Core
 	lastError error
 
 	allRolesLoaded bool
-	roles map[int64]string
+	roles          map[int64]string
+
+	allGroupsLoaded bool
+	groups          map[int64]string
+
+	// apps is the app IDs by name cache
+	apps map[string]int64
+
+	// stsEndpoint is the AWS STS endpoint. DefaultSTSEndpoint if empty.
+	stsEndpoint string
+
+	// credentialCache stores the AWS credentials obtained by GetAwsCredentials. Disabled if nil.
+	credentialCache *AwsCredentialCache
+
+	// prompter is used to interact with the user during MFA
+	prompter MFAPrompter
+
+	// otpSource provides OTP codes without user interaction, if set.
+	otpSource OTPSource
+
+	// devicePolicy selects the MFA device without user interaction, if set.
+	devicePolicy *MFADevicePolicy
+
+	// pushPolicy define how push notifications are polled.
+	pushPolicy *PushPolicy
 }
 
 // NewService create the main API object
@@ -34,6 +68,8 @@ func NewService(shard string, clientID string, clientSecret string, subdomain st
 	ret.SetLogLevel(loglevel)
 
 	ret.roles = make(map[int64]string)
+	ret.groups = make(map[int64]string)
+	ret.apps = make(map[string]int64)
 	return
 }
 
@@ -42,21 +78,75 @@ func (o *Service) SetLogLevel(loglevel logging.Level) {
 	SetLogLevel(loglevel)
 }
 
+// SetHTTPClient define the http client used to connect to OneLogin. See api.Core.SetHTTPClient
+func (o *Service) SetHTTPClient(client *http.Client) {
+	if o == nil || o.core == nil {
+		return
+	}
+	o.core.SetHTTPClient(client)
+}
+
+// SetRetryPolicy define how OneLogin API calls are retried when rate limited or on temporary failures.
+// See api.RetryPolicy. nil disables retries.
+func (o *Service) SetRetryPolicy(policy *api.RetryPolicy) {
+	if o == nil || o.core == nil {
+		return
+	}
+	o.core.SetRetryPolicy(policy)
+}
+
 // SAMLAuthenticate used to authenticate a user thanks to SAML
+// The mfa OTP code is used if not -1. See SAMLAuthenticateOTP.
+//
+// Deprecated: an int OTP code loses its leading zeros and cannot be an alphanumeric code (YubiKey). Use
+// SAMLAuthenticateOTP.
 func (o *Service) SAMLAuthenticate(user, pass, appID, ip string, mfa, deviceIndex int) (result *AwsSAMLAssertion, err error) {
-	if err = o.initCheck() ; err != nil {
+	return o.SAMLAuthenticateOTPWithContext(context.Background(), user, pass, appID, ip, intOTP(mfa), deviceIndex)
+}
+
+// SAMLAuthenticateWithContext is SAMLAuthenticate cancelled when ctx is done.
+//
+// Deprecated: Use SAMLAuthenticateOTPWithContext.
+func (o *Service) SAMLAuthenticateWithContext(ctx context.Context, user, pass, appID, ip string, mfa, deviceIndex int) (result *AwsSAMLAssertion, err error) {
+	return o.SAMLAuthenticateOTPWithContext(ctx, user, pass, appID, ip, intOTP(mfa), deviceIndex)
+}
+
+// intOTP return the OTP code of the deprecated int form. -1 is no code.
+func intOTP(mfa int) string {
+	if mfa == -1 {
+		return ""
+	}
+	return strconv.Itoa(mfa)
+}
+
+// SAMLAuthenticateOTP used to authenticate a user thanks to SAML
+// When a MFA is required, the device and OTP code are requested through the Service MFAPrompter (see SetMFAPrompter),
+// unless deviceIndex (not -1) and mfa (not empty) are given.
+// The device is selected by the MFADevicePolicy, if set (see SetMFADevicePolicy).
+// A non empty mfa is verified as the OTP code of the device, even for push capable devices (OneLogin Protect, Duo):
+// no push notification is sent.
+func (o *Service) SAMLAuthenticateOTP(user, pass, appID, ip, mfa string, deviceIndex int) (result *AwsSAMLAssertion, err error) {
+	return o.SAMLAuthenticateOTPWithContext(context.Background(), user, pass, appID, ip, mfa, deviceIndex)
+}
+
+// SAMLAuthenticateOTPWithContext is SAMLAuthenticateOTP cancelled when ctx is done.
+// The wait of a push notification approval is interrupted as well.
+// A push notification denied returns ErrPushDenied. If not approved in time, the OTP code is asked, or
+// ErrPushTimedOut is returned, as defined by the PushPolicy (see SetPushPolicy).
+func (o *Service) SAMLAuthenticateOTPWithContext(ctx context.Context, user, pass, appID, ip, mfa string, deviceIndex int) (result *AwsSAMLAssertion, err error) {
+	if err = o.initCheck(ctx); err != nil {
 		return
 	}
 
 	result = NewAwsSAMLAssertion(user, pass)
 	assertion := api.NewSAMLAssertionResult()
-	_, err = assertion.Post(o.core, user, pass, appID, o.core.SubDomain, ip)
+	response, err := assertion.PostWithContext(ctx, o.core, user, pass, appID, o.core.SubDomain, ip)
 
 	if err != nil {
 		return
 	}
 	if assertion.Status.Error {
-		err = fmt.Errorf("%d: %s", assertion.Status.Code, assertion.Status.Message)
+		err = api.NewAPIError(response, assertion.Status)
 		return
 	}
 	if assertion.Status.Type == "success" && assertion.Status.Message == "success" {
@@ -69,87 +159,227 @@ func (o *Service) SAMLAuthenticate(user, pass, appID, ip string, mfa, deviceInde
 	if err != nil {
 		return
 	}
+	if len(data) == 0 || len(data[0].Devices) == 0 {
+		err = fmt.Errorf("No MFA device available for user '%s': %w", user, api.ErrMFARequired)
+		return
+	}
 
-	// Display list of MFA Devices to use
-	fmt.Printf("\nMFA Required\n")
+	// Select the MFA Device to use
+	prompter := o.getPrompter()
+	prompter.Progress("MFA Required")
 	var device api.SAMLAssertionDevice
 	if deviceIndex == -1 {
-		fmt.Print("Authenticate using one of these devices:\n")
-		fmt.Print("-----------------------------------------------------------------------\n")
-		for index, device := range data[0].Devices {
-			fmt.Printf(" %d | %s\n", index, device.DeviceType)
+		if o.devicePolicy != nil {
+			deviceIndex, err = o.devicePolicy.Select(data[0].Devices)
+		} else {
+			deviceIndex, err = prompter.SelectDevice(data[0].Devices)
 		}
-		fmt.Print("-----------------------------------------------------------------------\n")
-
-		device = data[0].Devices[common.Select(0, len(data[0].Devices)-1)]
-	} else {
-		if deviceIndex >= len(data[0].Devices) {
-			err = fmt.Errorf("Invalid index %d. It must be between 0 and %d", deviceIndex, len(data[0].Devices)-1)
+		if err != nil {
 			return
 		}
-		device = data[0].Devices[deviceIndex]
-		fmt.Printf("Using the MFA device index %d (%s)\n", deviceIndex, device.DeviceType)
 	}
+	if deviceIndex < 0 || deviceIndex >= len(data[0].Devices) {
+		err = fmt.Errorf("Invalid index %d. It must be between 0 and %d", deviceIndex, len(data[0].Devices)-1)
+		return
+	}
+	device = data[0].Devices[deviceIndex]
+	prompter.Progress(fmt.Sprintf("Using the MFA device index %d (%s)", deviceIndex, device.DeviceType))
+
 	result.MfaVerifyInfo.DeviceID = device.DeviceID
 	result.MfaVerifyInfo.DeviceType = device.DeviceType
 
 	verifyFactor := api.NewVerifyFactorResult()
 
-	var MFACode int
-
-	defer fmt.Printf("\n")
-
-	switch device.DeviceType {
-	case "OneLogin SMS":
-		fmt.Printf("SMS with OTP token sent to device %d\n", device.DeviceID)
-		verifyFactor.Post(o.core, appID, device.DeviceID, data[0].StateToken, "", true)
-		MFACode = common.GetNumber("Enter the SMS OTP code received:")
-	case "OneLogin Protect":
-		fmt.Printf("PUSH with OTP token sent to device %d\n", device.DeviceID)
-		_, err = verifyFactor.Post(o.core, appID, device.DeviceID, data[0].StateToken, "", false)
-		// Push. Need to wait for OneLogin to confirm.
-		time.Sleep(time.Second * TimeSleepOnResponsePending)
-		for i := 0; i < MaxIterGetSAMLResponse; i++ {
-			fmt.Printf(".")
-			_, err = verifyFactor.Post(o.core, appID, device.DeviceID, data[0].StateToken, "", true)
-			if err != nil {
-				return
-			}
-			if verifyFactor.Status.Error {
-				err = fmt.Errorf("%d: %s", verifyFactor.Status.Code, verifyFactor.Status.Message)
-			} else if verifyFactor.Status.Type == "success" {
-				result.SetDecoded([]byte(verifyFactor.Data))
-				return
-			}
-
-			// recheck in couple of seconds
-			time.Sleep(time.Second * TimeSleepOnResponsePending)
-		}
-		fmt.Printf("\nUnable to get your device (%d) authentication.\n", device.DeviceID)
-		verifyFactor.Post(o.core, appID, device.DeviceID, data[0].StateToken, "", true)
-		MFACode = common.GetNumber("Enter the OneProtect OTP code from your mobile application:")
+	// An OTP source gives the code without user interaction.
+	otp, fromSource, err := o.sourceOTP(device)
+	if err != nil {
 		return
+	}
 
+	switch {
+	case fromSource:
+		prompter.Progress(fmt.Sprintf("Using the OTP code generated for device %d (%s)", device.DeviceID, device.DeviceType))
+	case mfa != "":
+		otp = mfa
+	case IsPushCapable(device.DeviceType):
+		// OneLogin Protect push notification, or Duo push/phone callback
+		var push PushResult
+		if push, err = o.waitPushApproval(ctx, prompter, appID, data[0].StateToken, device, result); err != nil {
+			return
+		}
+		switch push {
+		case PushApproved:
+			return
+		case PushDenied:
+			err = fmt.Errorf("Authentication denied on device %d (%s): %w", device.DeviceID, device.DeviceType, ErrPushDenied)
+			return
+		}
+		if !o.getPushPolicy().FallbackToOTP {
+			err = fmt.Errorf("Authentication not approved on device %d (%s): %w", device.DeviceID, device.DeviceType, ErrPushTimedOut)
+			return
+		}
+		prompter.Progress(fmt.Sprintf("\nAuthentication not approved on device %d in time. Falling back to OTP.", device.DeviceID))
+		if otp, err = prompter.GetOTP(device); err != nil {
+			return
+		}
+	case isOTPDelivered(device.DeviceType):
+		// OneLogin sends the OTP code by SMS, voice call or email.
+		prompter.Progress(fmt.Sprintf("OTP token sent to device %d (%s)", device.DeviceID, device.DeviceType))
+		response, err = verifyFactor.PostWithContext(ctx, o.core, appID, device.DeviceID, data[0].StateToken, "", false)
+		if err != nil {
+			return
+		}
+		if verifyFactor.Status.Error {
+			err = api.NewAPIError(response, verifyFactor.Status)
+			return
+		}
+		if otp, err = prompter.GetOTP(device); err != nil {
+			return
+		}
 	default:
-		fmt.Printf("Retrieve the OTP token from your device %d\n", device.DeviceID)
-		if mfa != -1 {
-			MFACode = mfa
-		} else {
-			MFACode = common.GetNumber(fmt.Sprintf("Enter the %s OTP code:", device.DeviceType))
+		// Google Authenticator, YubiKey, RSA SecurID or any other OTP device.
+		prompter.Progress(fmt.Sprintf("Retrieve the OTP token from your device %d (%s)", device.DeviceID, device.DeviceType))
+		if otp, err = prompter.GetOTP(device); err != nil {
+			return
 		}
 	}
-	result.MfaVerifyInfo.OTPToken = MFACode
-	_, err = verifyFactor.Post(o.core, appID, device.DeviceID, data[0].StateToken, fmt.Sprintf("%d", MFACode), true)
+	if err = ValidateOTP(device.DeviceType, otp); err != nil {
+		return
+	}
+	result.MfaVerifyInfo.OTPToken = otp
+	response, err = verifyFactor.PostWithContext(ctx, o.core, appID, device.DeviceID, data[0].StateToken, otp, true)
+	if err != nil {
+		return
+	}
 	if verifyFactor.Status.Type == "success" {
 		result.SetDecoded([]byte(verifyFactor.Data))
 		return
 	}
 	if verifyFactor.Status.Error {
-		err = fmt.Errorf("%s: %s", verifyFactor.Status.Type, verifyFactor.Status.Message)
+		err = api.NewAPIError(response, verifyFactor.Status)
 	}
 	return
 }
 
+// SetOTPSource define the OTPSource used by SAMLAuthenticateOTP to get OTP codes without user interaction.
+// Devices not handled by the source are still managed by the MFAPrompter. nil removes the source.
+func (o *Service) SetOTPSource(source OTPSource) {
+	if o == nil {
+		return
+	}
+	o.otpSource = source
+}
+
+// sourceOTP return the OTP code of the device from the OTPSource, if any handles it.
+func (o *Service) sourceOTP(device api.SAMLAssertionDevice) (string, bool, error) {
+	if o.otpSource == nil {
+		return "", false, nil
+	}
+	return o.otpSource.OTP(device)
+}
+
+// waitPushApproval sends a push notification to the device and polls OneLogin as defined by the PushPolicy, until
+// the authentication is approved, denied or the policy timeout is reached.
+// When approved, the SAML assertion is saved in result.
+func (o *Service) waitPushApproval(ctx context.Context, prompter MFAPrompter, appID, stateToken string, device api.SAMLAssertionDevice, result *AwsSAMLAssertion) (push PushResult, err error) {
+	policy := o.getPushPolicy().withDefaults()
+	verifyFactor := api.NewVerifyFactorResult()
+
+	prompter.Progress(fmt.Sprintf("PUSH notification sent to device %d (%s)", device.DeviceID, device.DeviceType))
+	response, err := verifyFactor.PostWithContext(ctx, o.core, appID, device.DeviceID, stateToken, "", false)
+	if err != nil {
+		return
+	}
+	if verifyFactor.Status.Error {
+		return PushPending, api.NewAPIError(response, verifyFactor.Status)
+	}
+
+	start := time.Now()
+	deadline := start.Add(policy.Timeout)
+	interval := policy.Interval
+	for attempt := 1; ; attempt++ {
+		// Wait before polling, but not after the deadline.
+		wait := interval
+		if remaining := time.Until(deadline); remaining < wait {
+			wait = remaining
+		}
+		if wait > 0 {
+			if err = common.SleepWithContext(ctx, wait); err != nil {
+				return
+			}
+		}
+
+		response, err = verifyFactor.PostWithContext(ctx, o.core, appID, device.DeviceID, stateToken, "", true)
+		var apiErr *api.APIError
+		switch {
+		case errors.As(err, &apiErr) && isPushDenied(apiErr.HTTPStatus, apiErr.Status):
+			push, err = PushDenied, nil
+		case err != nil:
+			return
+		case verifyFactor.Status.Error && isPushDenied(response.StatusCode, verifyFactor.Status):
+			push = PushDenied
+		case verifyFactor.Status.Error:
+			return PushPending, api.NewAPIError(response, verifyFactor.Status)
+		case verifyFactor.Status.Type == "success":
+			push = PushApproved
+			result.MfaVerifyInfo.PushResult = push
+			result.SetDecoded([]byte(verifyFactor.Data))
+		case !time.Now().Before(deadline):
+			push = PushTimedOut
+		default:
+			prompter.PushPending(device)
+			policy.progress(device, attempt, start, PushPending)
+			interval = policy.nextInterval(interval)
+			continue
+		}
+		logger.Infof("Push notification on device %d: %s", device.DeviceID, push)
+		policy.progress(device, attempt, start, push)
+		return
+	}
+}
+
+// SetPushPolicy define how SAMLAuthenticateOTP waits for push notifications approval. nil restores NewPushPolicy().
+func (o *Service) SetPushPolicy(policy *PushPolicy) {
+	if o == nil {
+		return
+	}
+	o.pushPolicy = policy
+}
+
+// getPushPolicy return the PushPolicy to use. By default, this is NewPushPolicy().
+func (o *Service) getPushPolicy() *PushPolicy {
+	if o.pushPolicy == nil {
+		o.pushPolicy = NewPushPolicy()
+	}
+	return o.pushPolicy
+}
+
+// SetMFADevicePolicy define the MFADevicePolicy used by SAMLAuthenticateOTP to select the MFA device, instead of asking
+// the MFAPrompter. nil restores the MFAPrompter selection.
+func (o *Service) SetMFADevicePolicy(policy *MFADevicePolicy) {
+	if o == nil {
+		return
+	}
+	o.devicePolicy = policy
+}
+
+// SetMFAPrompter define the MFAPrompter used by SAMLAuthenticateOTP to interact with the user.
+// A nil prompter restores the default TerminalPrompter.
+func (o *Service) SetMFAPrompter(prompter MFAPrompter) {
+	if o == nil {
+		return
+	}
+	o.prompter = prompter
+}
+
+// getPrompter return the MFAPrompter to use. By default, this is a TerminalPrompter on os.Stdin/os.Stdout
+func (o *Service) getPrompter() MFAPrompter {
+	if o.prompter == nil {
+		o.prompter = NewTerminalPrompter(os.Stdin, os.Stdout)
+	}
+	return o.prompter
+}
+
 // CleanError cleanup the last error reported by onelogin API call.
 func (o *Service) CleanError() {
 	if o == nil {
@@ -167,11 +397,11 @@ func (o *Service) setError(err error) error {
 }
 
 // initCheck basically check initial onelogin object status and obtain API access.
-func (o *Service) initCheck() (_ error) {
+func (o *Service) initCheck(ctx context.Context) (_ error) {
 	if o == nil {
 		return errors.New("onelogin.Service is nil")
 	}
-	if  o.core == nil {
+	if o.core == nil {
 		return errors.New("onelogin.api.Core is nil")
 	}
 
@@ -179,40 +409,58 @@ func (o *Service) initCheck() (_ error) {
 		return fmt.Errorf("onelogin.Core is always in error: %s", o.lastError)
 	}
 
-	if o.core.Token == nil || o.core.Token.AccessToken == "" {
-		if err := o.setError(o.core.ObtainAPIAccess()) ; err != nil {
-			return err
-		}
-	}
-	return
+	// Obtain the token or renew it if it is about to expire.
+	// A failure may be temporary, so it is not kept as the Service last error.
+	return o.core.EnsureAPIAccessWithContext(ctx)
 }
 
 // GetRoles return the list of all roles from OneLogin
 func (o *Service) GetRoles() (ret map[int64]string, err error) {
-	if err = o.initCheck() ; err != nil {
+	return o.GetRolesWithContext(context.Background())
+}
+
+// GetRolesWithContext is GetRoles cancelled when ctx is done.
+func (o *Service) GetRolesWithContext(ctx context.Context) (ret map[int64]string, err error) {
+	if err = o.initCheck(ctx); err != nil {
 		return
 	}
 
 	if o.allRolesLoaded {
 		ret = o.roles
-		return 
-	}
-
-	roles := api.NewGetRoles()
-	if _, err = roles.Get(o.core) ; err != nil {
-		return ret, o.setError(err)
+		return
 	}
 
-	for _, role := range roles.Data {
+	// Read all pages of roles
+	roles := api.NewGetRoles().Iterate(ctx, o.core, nil)
+	for roles.Next() {
+		role := roles.Role()
 		o.roles[role.ID] = role.Name
 	}
+	if err = roles.Err(); err != nil {
+		return ret, o.setError(err)
+	}
+	o.allRolesLoaded = true
 	ret = o.roles
 	return
 }
 
+// TokenLifetime return the remaining time before the current OneLogin API token expires.
+// The token is renewed automatically by API calls, before it expires.
+func (o *Service) TokenLifetime() time.Duration {
+	if o == nil || o.core == nil {
+		return 0
+	}
+	return o.core.TokenLifetime()
+}
+
 // GetAPI provide the OneLogin api obejct and access to it. (access token)
 func (o *Service) GetAPI() (apiCore *api.Core, err error) {
-	if err = o.initCheck() ; err != nil {
+	return o.GetAPIWithContext(context.Background())
+}
+
+// GetAPIWithContext is GetAPI cancelled when ctx is done.
+func (o *Service) GetAPIWithContext(ctx context.Context) (apiCore *api.Core, err error) {
+	if err = o.initCheck(ctx); err != nil {
 		return
 	}
 	apiCore = o.core
@@ -221,17 +469,22 @@ func (o *Service) GetAPI() (apiCore *api.Core, err error) {
 
 // GetRoleName return a role name from the role ID
 func (o *Service) GetRoleName(id int64) (ret string, err error) {
-	if err = o.initCheck() ; err != nil {
+	return o.GetRoleNameWithContext(context.Background(), id)
+}
+
+// GetRoleNameWithContext is GetRoleName cancelled when ctx is done.
+func (o *Service) GetRoleNameWithContext(ctx context.Context, id int64) (ret string, err error) {
+	if err = o.initCheck(ctx); err != nil {
 		return
 	}
 
-	if v, found := o.roles[id] ; found {
+	if v, found := o.roles[id]; found {
 		return v, nil
 	}
 
 	role := api.NewGetRoleByID()
 
-	if _, err = role.Get(o.core, id) ; err != nil {
+	if _, err = role.GetWithContext(ctx, o.core, id); err != nil {
 		return ret, err
 	}
 	if len(role.Data) >= 1 {
@@ -0,0 +1,68 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+// GetAppID return the ID of the OneLogin app named name, as expected by SAMLAuthenticateOTP.
+// The name must match exactly one app.
+func (o *Service) GetAppID(name string) (ret string, err error) {
+	return o.GetAppIDWithContext(context.Background(), name)
+}
+
+// GetAppIDWithContext is GetAppID cancelled when ctx is done.
+func (o *Service) GetAppIDWithContext(ctx context.Context, name string) (ret string, err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	if id, found := o.apps[name]; found {
+		return strconv.FormatInt(id, 10), nil
+	}
+
+	apps := api.NewGetApps().Iterate(ctx, o.core, api.AppFilter{Name: name}.QueryOptions())
+	var found []api.App
+	for apps.Next() {
+		// The name filter may match partially. Keep exact matches only.
+		if app := apps.App(); app.Name == name {
+			found = append(found, app)
+		}
+	}
+	if err = apps.Err(); err != nil {
+		return
+	}
+
+	switch len(found) {
+	case 0:
+		err = fmt.Errorf("No app named '%s': %w", name, api.ErrNotFound)
+	case 1:
+		o.apps[name] = found[0].ID
+		ret = strconv.FormatInt(found[0].ID, 10)
+	default:
+		err = fmt.Errorf("%d apps are named '%s'. Use the app ID instead", len(found), name)
+	}
+	return
+}
+
+// GetUserApps return the apps assigned to the user.
+func (o *Service) GetUserApps(userID int64) (ret api.UserApps, err error) {
+	return o.GetUserAppsWithContext(context.Background(), userID)
+}
+
+// GetUserAppsWithContext is GetUserApps cancelled when ctx is done.
+func (o *Service) GetUserAppsWithContext(ctx context.Context, userID int64) (ret api.UserApps, err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	apps := api.NewGetUserApps()
+	if _, err = apps.GetWithContext(ctx, o.core, userID); err != nil {
+		return
+	}
+	ret = apps.Data
+	return
+}
@@ -0,0 +1,88 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SetSTSEndpoint define the AWS STS endpoint used by AssumeAwsRole. An empty endpoint restores DefaultSTSEndpoint.
+func (o *Service) SetSTSEndpoint(endpoint string) {
+	if o == nil {
+		return
+	}
+	o.stsEndpoint = endpoint
+}
+
+// AssumeAwsRole exchanges the SAML assertion obtained with SAMLAuthenticateOTP against AWS temporary credentials of the
+// role selected.
+// If duration is 0, the SessionDuration of the assertion is used, if any. Otherwise, STS default applies (1 hour).
+func (o *Service) AssumeAwsRole(assertion *AwsSAMLAssertion, selector AwsRoleSelector, duration time.Duration) (*AwsCredentials, error) {
+	return o.AssumeAwsRoleWithContext(context.Background(), assertion, selector, duration)
+}
+
+// AssumeAwsRoleWithContext is AssumeAwsRole cancelled when ctx is done.
+func (o *Service) AssumeAwsRoleWithContext(ctx context.Context, assertion *AwsSAMLAssertion, selector AwsRoleSelector, duration time.Duration) (ret *AwsCredentials, err error) {
+	if o == nil || o.core == nil {
+		return nil, errors.New("onelogin.Service is nil")
+	}
+
+	data, err := assertion.Parse()
+	if err != nil {
+		return
+	}
+	role, err := selector.Select(data.AwsRoles)
+	if err != nil {
+		return
+	}
+	if duration == 0 {
+		duration = data.SessionDuration
+	}
+
+	logger.Infof("Assuming AWS role %s", role.RoleARN)
+	sts := NewSTSClient(o.stsEndpoint, o.core.GetHTTPClient())
+	return sts.AssumeRoleWithSAML(ctx, role, string(assertion.EncodedSamlResponse), duration)
+}
+
+// SetAwsCredentialCache define the cache used by GetAwsCredentials. nil disables the cache.
+func (o *Service) SetAwsCredentialCache(cache *AwsCredentialCache) {
+	if o == nil {
+		return
+	}
+	o.credentialCache = cache
+}
+
+// GetAwsCredentials return the AWS credentials of the role selected, from the AwsCredentialCache if they are still
+// valid. Otherwise, the user is authenticated with SAMLAuthenticateOTP (MFA may be requested), the role is assumed and
+// the credentials are cached.
+func (o *Service) GetAwsCredentials(user, pass, appID string, selector AwsRoleSelector, duration time.Duration) (*AwsCredentials, error) {
+	return o.GetAwsCredentialsWithContext(context.Background(), user, pass, appID, selector, duration)
+}
+
+// GetAwsCredentialsWithContext is GetAwsCredentials cancelled when ctx is done.
+func (o *Service) GetAwsCredentialsWithContext(ctx context.Context, user, pass, appID string, selector AwsRoleSelector, duration time.Duration) (ret *AwsCredentials, err error) {
+	if o == nil || o.core == nil {
+		return nil, errors.New("onelogin.Service is nil")
+	}
+
+	key := AwsCredentialCacheKey{Subdomain: o.core.SubDomain, Username: user, AppID: appID, Role: selector.Role}
+	if ret = o.credentialCache.Get(key); ret != nil {
+		logger.Infof("Using cached AWS credentials of %s, valid until %s", key, ret.Expiration)
+		return
+	}
+
+	assertion, err := o.SAMLAuthenticateOTPWithContext(ctx, user, pass, appID, "", "", -1)
+	if err != nil {
+		return
+	}
+	if ret, err = o.AssumeAwsRoleWithContext(ctx, assertion, selector, duration); err != nil {
+		return
+	}
+	if o.credentialCache != nil {
+		if err = o.credentialCache.Put(key, ret); err != nil {
+			logger.Warningf("Unable to cache AWS credentials of %s: %s", key, err)
+			err = nil
+		}
+	}
+	return
+}
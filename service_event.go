@@ -0,0 +1,138 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/clarsonneur/onelogin/api"
+	"github.com/clarsonneur/onelogin/common"
+)
+
+// EventCheckpoint records the last event processed from an events stream, so that the stream can be resumed.
+type EventCheckpoint struct {
+	LastEventID   int64     `json:"last_event_id"`
+	LastCreatedAt time.Time `json:"last_created_at"`
+}
+
+// LoadEventCheckpoint reads a checkpoint saved with Save.
+// If the file does not exist, an empty checkpoint is returned, to stream events from the beginning.
+func LoadEventCheckpoint(path string) (ret *EventCheckpoint, err error) {
+	ret = new(EventCheckpoint)
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ret, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, ret); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Save writes the checkpoint to the file, atomically.
+func (c *EventCheckpoint) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return common.WriteFileAtomic(path, data, 0600)
+}
+
+// Update records the event as processed. Call it once the event has been handled.
+func (c *EventCheckpoint) Update(event api.Event) {
+	if c == nil {
+		return
+	}
+	c.LastEventID = event.ID
+	c.LastCreatedAt = event.CreatedAt
+}
+
+// after return true if the event has not been processed yet.
+func (c *EventCheckpoint) after(event api.Event) bool {
+	if c == nil || c.LastCreatedAt.IsZero() {
+		return true
+	}
+	if event.CreatedAt.Equal(c.LastCreatedAt) {
+		return event.ID > c.LastEventID
+	}
+	return event.CreatedAt.After(c.LastCreatedAt)
+}
+
+// GetEventTypes return the list of all event types from OneLogin, by ID.
+func (o *Service) GetEventTypes() (ret map[int64]api.EventType, err error) {
+	return o.GetEventTypesWithContext(context.Background())
+}
+
+// GetEventTypesWithContext is GetEventTypes cancelled when ctx is done.
+func (o *Service) GetEventTypesWithContext(ctx context.Context) (ret map[int64]api.EventType, err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	types := api.NewGetEventTypes()
+	if _, err = types.GetWithContext(ctx, o.core); err != nil {
+		return
+	}
+	ret = make(map[int64]api.EventType)
+	for _, eventType := range types.Data {
+		ret[eventType.ID] = eventType
+	}
+	return
+}
+
+// StreamEvents sends to the returned channel all events matching the filter, oldest first, following pagination.
+// If checkpoint is given, events up to the checkpoint are skipped. The caller updates the checkpoint with the events
+// processed (see EventCheckpoint.Update), to resume the stream later.
+//
+// Both channels are closed when all events have been sent, or on error. The error channel receives the error which
+// stopped the stream, if any. Cancel ctx to stop the stream.
+func (o *Service) StreamEvents(ctx context.Context, filter api.EventFilter, checkpoint *EventCheckpoint) (<-chan api.Event, <-chan error) {
+	events := make(chan api.Event)
+	errs := make(chan error, 1)
+
+	if err := o.initCheck(ctx); err != nil {
+		errs <- err
+		close(events)
+		close(errs)
+		return events, errs
+	}
+
+	if checkpoint != nil && !checkpoint.LastCreatedAt.IsZero() &&
+		(filter.Since == nil || filter.Since.Before(checkpoint.LastCreatedAt)) {
+		since := checkpoint.LastCreatedAt
+		filter.Since = &since
+	}
+	from := EventCheckpoint{}
+	if checkpoint != nil {
+		from = *checkpoint
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+
+		iterator := api.NewGetEvents().Iterate(ctx, o.core, filter.QueryOptions().Sort("created_at", true))
+		for iterator.Next() {
+			event := iterator.Event()
+			if !from.after(event) {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+		if err := iterator.Err(); err != nil {
+			errs <- err
+		}
+	}()
+	return events, errs
+}
@@ -0,0 +1,88 @@
+package onelogin
+
+import (
+	"context"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+// GetGroups return the list of all groups from OneLogin
+func (o *Service) GetGroups() (ret map[int64]string, err error) {
+	return o.GetGroupsWithContext(context.Background())
+}
+
+// GetGroupsWithContext is GetGroups cancelled when ctx is done.
+func (o *Service) GetGroupsWithContext(ctx context.Context) (ret map[int64]string, err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	if o.allGroupsLoaded {
+		ret = o.groups
+		return
+	}
+
+	// Read all pages of groups
+	groups := api.NewGetGroups().Iterate(ctx, o.core, nil)
+	for groups.Next() {
+		group := groups.Group()
+		o.groups[group.ID] = group.Name
+	}
+	if err = groups.Err(); err != nil {
+		return ret, o.setError(err)
+	}
+	o.allGroupsLoaded = true
+	ret = o.groups
+	return
+}
+
+// GetGroupName return a group name from the group ID
+func (o *Service) GetGroupName(id int64) (ret string, err error) {
+	return o.GetGroupNameWithContext(context.Background(), id)
+}
+
+// GetGroupNameWithContext is GetGroupName cancelled when ctx is done.
+func (o *Service) GetGroupNameWithContext(ctx context.Context, id int64) (ret string, err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	if v, found := o.groups[id]; found {
+		return v, nil
+	}
+
+	group := api.NewGetGroupByID()
+
+	if _, err = group.GetWithContext(ctx, o.core, id); err != nil {
+		return ret, err
+	}
+	if len(group.Data) >= 1 {
+		ret = group.Data[0].Name
+		o.groups[id] = ret
+	}
+	return
+}
+
+// SetUserGroup assign the user to the group. A groupID of 0 removes the user from its group.
+func (o *Service) SetUserGroup(userID, groupID int64) (err error) {
+	return o.SetUserGroupWithContext(context.Background(), userID, groupID)
+}
+
+// SetUserGroupWithContext is SetUserGroup cancelled when ctx is done.
+func (o *Service) SetUserGroupWithContext(ctx context.Context, userID, groupID int64) (err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	input := api.NewPutUserRequest()
+	if groupID == 0 {
+		input.Clear("group_id")
+	} else {
+		if _, err = o.GetGroupNameWithContext(ctx, groupID); err != nil {
+			return
+		}
+		input.SetGroupID(groupID)
+	}
+	_, err = api.NewPutUserByID().PutWithContext(ctx, o.core, userID, *input)
+	return
+}
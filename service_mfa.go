@@ -0,0 +1,49 @@
+package onelogin
+
+import (
+	"context"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+// GetUserMFADevices return the MFA devices enrolled by the user.
+func (o *Service) GetUserMFADevices(userID int64) (ret api.Devices, err error) {
+	return o.GetUserMFADevicesWithContext(context.Background(), userID)
+}
+
+// GetUserMFADevicesWithContext is GetUserMFADevices cancelled when ctx is done.
+func (o *Service) GetUserMFADevicesWithContext(ctx context.Context, userID int64) (ret api.Devices, err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	devices := api.NewGetEnrolledFactors()
+	if _, err = devices.GetWithContext(ctx, o.core, userID); err != nil {
+		return
+	}
+	ret = devices.Data.OTPDevices
+	return
+}
+
+// ResetUserMFA removes all MFA devices enrolled by the user, so that the user can enroll them again.
+// It returns the devices removed. On error, devices removed before the error are returned.
+func (o *Service) ResetUserMFA(userID int64) (removed api.Devices, err error) {
+	return o.ResetUserMFAWithContext(context.Background(), userID)
+}
+
+// ResetUserMFAWithContext is ResetUserMFA cancelled when ctx is done.
+func (o *Service) ResetUserMFAWithContext(ctx context.Context, userID int64) (removed api.Devices, err error) {
+	devices, err := o.GetUserMFADevicesWithContext(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	for _, device := range devices {
+		logger.Infof("Removing MFA device %d (%s) of user %d", device.DeviceID, device.DeviceType, userID)
+		if _, err = api.NewRemoveFactor().DeleteWithContext(ctx, o.core, userID, device.DeviceID); err != nil {
+			return
+		}
+		removed = append(removed, device)
+	}
+	return
+}
@@ -0,0 +1,156 @@
+package onelogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/clarsonneur/onelogin/api"
+	"github.com/op/go-logging"
+)
+
+// mfaStub is a OneLogin API stand-in requiring a MFA with the devices given. It delivers tokens "token-1",
+// "token-2", ... verify_factor calls are recorded and answered by verify.
+type mfaStub struct {
+	*httptest.Server
+
+	lock    sync.Mutex
+	tokens  int
+	devices []api.SAMLAssertionDevice
+	verifys []api.VerifyFactorRequest
+	// revoked is a token rejected by verify_factor (401), as if expired or revoked. The call is not recorded.
+	revoked string
+	// verify answers the verify_factor call number call (1 based).
+	verify func(w http.ResponseWriter, request api.VerifyFactorRequest, call int)
+}
+
+func newMFAStub(t *testing.T, verify func(w http.ResponseWriter, request api.VerifyFactorRequest, call int),
+	devices ...api.SAMLAssertionDevice) (ret *mfaStub) {
+	ret = &mfaStub{devices: devices, verify: verify}
+	ret.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + api.TokenURIPath:
+			ret.lock.Lock()
+			ret.tokens++
+			fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":36000}`, ret.tokens)
+			ret.lock.Unlock()
+		case "/" + api.SAMLAssertionURIPath:
+			data, _ := json.Marshal([]api.SAMLAssertionDataResult{{StateToken: "state", Devices: ret.devices}})
+			fmt.Fprintf(w, `{"status":{"error":false,"code":200,"type":"success","message":"MFA is required for this user"},"data":%s}`, data)
+		case "/" + api.VerifyFactorURIPath:
+			request := api.VerifyFactorRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("verify_factor: %s", err)
+			}
+			ret.lock.Lock()
+			if ret.revoked != "" && r.Header.Get("Authorization") == "bearer:"+ret.revoked {
+				ret.lock.Unlock()
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"status":{"error":true,"code":401,"type":"Unauthorized","message":"Authentication Failure"}}`)
+				return
+			}
+			ret.verifys = append(ret.verifys, request)
+			call := len(ret.verifys)
+			ret.lock.Unlock()
+			ret.verify(w, request, call)
+		default:
+			t.Errorf("unexpected call %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return
+}
+
+// requests return the verify_factor calls received.
+func (s *mfaStub) requests() []api.VerifyFactorRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]api.VerifyFactorRequest{}, s.verifys...)
+}
+
+func (s *mfaStub) service(prompter MFAPrompter) *Service {
+	ret := NewService("us", "id", "secret", "test", logging.ERROR)
+	ret.core.CustomURL = s.URL
+	ret.SetMFAPrompter(prompter)
+	return ret
+}
+
+func writeVerified(w http.ResponseWriter) {
+	fmt.Fprint(w, `{"status":{"error":false,"code":200,"type":"success","message":"Success"},"data":"PHNhbWxwOlJlc3BvbnNlLz4="}`)
+}
+
+func TestSAMLAuthenticateOTPSkipsPush(t *testing.T) {
+	stub := newMFAStub(t, func(w http.ResponseWriter, request api.VerifyFactorRequest, call int) {
+		writeVerified(w)
+	}, api.SAMLAssertionDevice{DeviceID: 1, DeviceType: DeviceTypeOneLoginProtect})
+	defer stub.Close()
+
+	prompter := NewScriptedPrompter(0)
+	assertion, err := stub.service(prompter).SAMLAuthenticateOTP("me", "password", "1", "", "012345", -1)
+	if err != nil {
+		t.Fatalf("SAMLAuthenticateOTP: %s", err)
+	}
+	if requests := stub.requests(); len(requests) != 1 || requests[0].OTPToken != "012345" {
+		t.Errorf("verify_factor requests = %+v, want only the OTP verification", requests)
+	}
+	if prompter.PushCount != 0 || assertion.MfaVerifyInfo.OTPToken != "012345" {
+		t.Errorf("push count = %d, OTP = %s", prompter.PushCount, assertion.MfaVerifyInfo.OTPToken)
+	}
+}
+
+func TestSAMLAuthenticateDeprecatedIntOTP(t *testing.T) {
+	stub := newMFAStub(t, func(w http.ResponseWriter, request api.VerifyFactorRequest, call int) {
+		writeVerified(w)
+	}, api.SAMLAssertionDevice{DeviceID: 1, DeviceType: DeviceTypeGoogleAuthenticator})
+	defer stub.Close()
+
+	if _, err := stub.service(NewScriptedPrompter(0)).SAMLAuthenticate("me", "password", "1", "", 123456, 0); err != nil {
+		t.Fatalf("SAMLAuthenticate: %s", err)
+	}
+	if requests := stub.requests(); len(requests) != 1 || requests[0].OTPToken != "123456" {
+		t.Errorf("verify_factor requests = %+v", requests)
+	}
+
+	// -1 is no code: the prompter gives it.
+	if _, err := stub.service(NewScriptedPrompter(0, "654321")).SAMLAuthenticate("me", "password", "1", "", -1, -1); err != nil {
+		t.Fatalf("SAMLAuthenticate: %s", err)
+	}
+	if requests := stub.requests(); len(requests) != 2 || requests[1].OTPToken != "654321" {
+		t.Errorf("verify_factor requests = %+v", requests)
+	}
+}
+
+func TestSAMLAuthenticateOTPDelivered(t *testing.T) {
+	stub := newMFAStub(t, func(w http.ResponseWriter, request api.VerifyFactorRequest, call int) {
+		if call == 1 {
+			fmt.Fprint(w, `{"status":{"error":false,"code":200,"type":"pending","message":"SMS token sent to your phone"}}`)
+			return
+		}
+		writeVerified(w)
+	}, api.SAMLAssertionDevice{DeviceID: 7, DeviceType: DeviceTypeOneLoginSMS})
+	defer stub.Close()
+
+	prompter := NewScriptedPrompter(0, "0123456")
+	assertion, err := stub.service(prompter).SAMLAuthenticateOTP("me", "password", "1", "", "", -1)
+	if err != nil {
+		t.Fatalf("SAMLAuthenticateOTP: %s", err)
+	}
+	requests := stub.requests()
+	if len(requests) != 2 {
+		t.Fatalf("verify_factor requests = %+v, want the SMS trigger then the verification", requests)
+	}
+	// The trigger asks OneLogin to send the code, without code.
+	if trigger := requests[0]; trigger.DeviceID != "7" || trigger.OTPToken != "" || trigger.DoNotNotify {
+		t.Errorf("trigger request = %+v", trigger)
+	}
+	// Then the code received is verified, without sending a new one.
+	if verify := requests[1]; verify.DeviceID != "7" || verify.OTPToken != "0123456" || !verify.DoNotNotify {
+		t.Errorf("verify request = %+v", verify)
+	}
+	if assertion.MfaVerifyInfo.OTPToken != "0123456" || len(assertion.SamlResponse) == 0 {
+		t.Errorf("assertion = %+v", assertion.MfaVerifyInfo)
+	}
+}
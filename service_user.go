@@ -0,0 +1,208 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+// CreateUser creates a OneLogin user from the user fields and return the user created.
+func (o *Service) CreateUser(user api.User) (ret api.User, err error) {
+	return o.CreateUserWithContext(context.Background(), user)
+}
+
+// CreateUserWithContext is CreateUser cancelled when ctx is done.
+func (o *Service) CreateUserWithContext(ctx context.Context, user api.User) (ret api.User, err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	created := api.NewCreateUser()
+	if _, err = created.PostWithContext(ctx, o.core, api.NewCreateUserRequest(user)); err != nil {
+		return
+	}
+	if len(created.Data) == 0 {
+		err = fmt.Errorf("OneLogin did not return the user '%s' created", user.Username)
+		return
+	}
+	ret = created.Data[0]
+	return
+}
+
+// DeleteUser deletes the OneLogin user.
+func (o *Service) DeleteUser(id int64) (err error) {
+	return o.DeleteUserWithContext(context.Background(), id)
+}
+
+// DeleteUserWithContext is DeleteUser cancelled when ctx is done.
+func (o *Service) DeleteUserWithContext(ctx context.Context, id int64) (err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	_, err = api.NewDeleteUserByID().DeleteWithContext(ctx, o.core, id)
+	return
+}
+
+// LockUser locks the OneLogin user for the given duration, rounded up to the minute.
+// A duration of 0 locks the user for the duration defined by the user policy.
+func (o *Service) LockUser(id int64, duration time.Duration) (err error) {
+	return o.LockUserWithContext(context.Background(), id, duration)
+}
+
+// LockUserWithContext is LockUser cancelled when ctx is done.
+func (o *Service) LockUserWithContext(ctx context.Context, id int64, duration time.Duration) (err error) {
+	if duration < 0 {
+		return fmt.Errorf("Invalid lock duration %s", duration)
+	}
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	input := api.LockUserRequest{
+		LockedUntil: int((duration + time.Minute - 1) / time.Minute),
+	}
+	_, err = api.NewLockUser().PutWithContext(ctx, o.core, id, input)
+	return
+}
+
+// LogUserOut terminates all the OneLogin sessions of the user.
+func (o *Service) LogUserOut(id int64) (err error) {
+	return o.LogUserOutWithContext(context.Background(), id)
+}
+
+// LogUserOutWithContext is LogUserOut cancelled when ctx is done.
+func (o *Service) LogUserOutWithContext(ctx context.Context, id int64) (err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	_, err = api.NewLogUserOut().PutWithContext(ctx, o.core, id)
+	return
+}
+
+// GetUserRoles return the roles (ID and name) assigned to the user.
+func (o *Service) GetUserRoles(userID int64) (ret map[int64]string, err error) {
+	return o.GetUserRolesWithContext(context.Background(), userID)
+}
+
+// GetUserRolesWithContext is GetUserRoles cancelled when ctx is done.
+func (o *Service) GetUserRolesWithContext(ctx context.Context, userID int64) (ret map[int64]string, err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	userRoles := api.NewGetUserRoles()
+	if _, err = userRoles.GetWithContext(ctx, o.core, userID); err != nil {
+		return
+	}
+
+	ret = make(map[int64]string)
+	for _, id := range userRoles.RolesID() {
+		if ret[id], err = o.GetRoleNameWithContext(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// SetUserRoles update the user roles to match exactly the desired list of role IDs.
+// Missing roles are added and roles not desired are removed. It returns the list of roles IDs added and removed.
+// An error is returned if a desired role does not exist.
+func (o *Service) SetUserRoles(userID int64, desired []int64) (added, removed []int64, err error) {
+	return o.SetUserRolesWithContext(context.Background(), userID, desired)
+}
+
+// SetUserRolesWithContext is SetUserRoles cancelled when ctx is done.
+func (o *Service) SetUserRolesWithContext(ctx context.Context, userID int64, desired []int64) (added, removed []int64, err error) {
+	var roles map[int64]string
+	if roles, err = o.GetRolesWithContext(ctx); err != nil {
+		return
+	}
+
+	userRoles := api.NewGetUserRoles()
+	if _, err = userRoles.GetWithContext(ctx, o.core, userID); err != nil {
+		return
+	}
+
+	current := make(map[int64]bool)
+	for _, id := range userRoles.RolesID() {
+		current[id] = true
+	}
+
+	wanted := make(map[int64]bool)
+	for _, id := range desired {
+		if _, found := roles[id]; !found {
+			return nil, nil, fmt.Errorf("Role %d does not exist: %w", id, api.ErrNotFound)
+		}
+		if !wanted[id] && !current[id] {
+			added = append(added, id)
+		}
+		wanted[id] = true
+	}
+	for _, id := range userRoles.RolesID() {
+		if !wanted[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	if len(added) > 0 {
+		logger.Infof("Adding roles %s to user %d", o.roleNames(added), userID)
+		if _, err = api.NewAddUserRoles().PutWithContext(ctx, o.core, userID, added); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(removed) > 0 {
+		logger.Infof("Removing roles %s from user %d", o.roleNames(removed), userID)
+		if _, err = api.NewRemoveUserRoles().PutWithContext(ctx, o.core, userID, removed); err != nil {
+			return added, nil, err
+		}
+	}
+	return
+}
+
+// roleNames return the role names from the roles cache, for logging.
+func (o *Service) roleNames(ids []int64) (ret []string) {
+	for _, id := range ids {
+		if name, found := o.roles[id]; found {
+			ret = append(ret, fmt.Sprintf("%d (%s)", id, name))
+		} else {
+			ret = append(ret, fmt.Sprintf("%d", id))
+		}
+	}
+	return
+}
+
+// SetUserPassword set the user password, sent in clear text to OneLogin through TLS.
+// If validatePolicy is true, OneLogin checks the password against the user password policy.
+func (o *Service) SetUserPassword(userID int64, password string, validatePolicy bool) (err error) {
+	return o.SetUserPasswordWithContext(context.Background(), userID, password, validatePolicy)
+}
+
+// SetUserPasswordWithContext is SetUserPassword cancelled when ctx is done.
+func (o *Service) SetUserPasswordWithContext(ctx context.Context, userID int64, password string, validatePolicy bool) (err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	_, err = api.NewSetPasswordClearText().PutWithContext(ctx, o.core, userID, api.Password(password), validatePolicy)
+	return
+}
+
+// SetUserPasswordHash set the user password from a salted SHA-256 hash (see api.SaltedSHA256).
+// Used to migrate passwords without knowing them.
+func (o *Service) SetUserPasswordHash(userID int64, hash api.Password, salt string) (err error) {
+	return o.SetUserPasswordHashWithContext(context.Background(), userID, hash, salt)
+}
+
+// SetUserPasswordHashWithContext is SetUserPasswordHash cancelled when ctx is done.
+func (o *Service) SetUserPasswordHashWithContext(ctx context.Context, userID int64, hash api.Password, salt string) (err error) {
+	if err = o.initCheck(ctx); err != nil {
+		return
+	}
+
+	_, err = api.NewSetPasswordUsingSalt().PutWithContext(ctx, o.core, userID, hash, salt)
+	return
+}
@@ -0,0 +1,268 @@
+package onelogin
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+// TOTP algorithms.
+const (
+	TOTPAlgorithmSHA1   = "SHA1"
+	TOTPAlgorithmSHA256 = "SHA256"
+	TOTPAlgorithmSHA512 = "SHA512"
+)
+
+// TOTP generates Time-based One-Time Passwords (RFC 6238) from a shared secret, as an authenticator application does.
+type TOTP struct {
+	// Secret is the shared secret, decoded.
+	Secret []byte
+	// Algorithm is the HMAC hash: TOTPAlgorithmSHA1 (default), TOTPAlgorithmSHA256 or TOTPAlgorithmSHA512
+	Algorithm string
+	// Digits is the code length. Default is 6.
+	Digits int
+	// Period is the code validity. Default is 30 seconds.
+	Period time.Duration
+	// Issuer and Account are the otpauth URI label.
+	Issuer  string
+	Account string
+}
+
+// NewTOTP creates a TOTP with default values (SHA1, 6 digits, 30 seconds) from a base32 encoded secret.
+func NewTOTP(secret string) (ret *TOTP, err error) {
+	ret = new(TOTP)
+	if ret.Secret, err = decodeTOTPSecret(secret); err != nil {
+		return nil, err
+	}
+	ret.Algorithm = TOTPAlgorithmSHA1
+	ret.Digits = 6
+	ret.Period = 30 * time.Second
+	return
+}
+
+// ParseTOTPURI creates a TOTP from an otpauth URI, as given by the QR code of a TOTP factor enrollment.
+// Ex: otpauth://totp/OneLogin:me@myCompany.com?secret=JBSWY3DPEHPK3PXP&issuer=OneLogin&digits=6&period=30
+func ParseTOTPURI(uri string) (ret *TOTP, err error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		return nil, fmt.Errorf("'%s://%s' is not an otpauth://totp/ URI", u.Scheme, u.Host)
+	}
+
+	query := u.Query()
+	if ret, err = NewTOTP(query.Get("secret")); err != nil {
+		return nil, err
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if parts := strings.SplitN(label, ":", 2); len(parts) == 2 {
+		ret.Issuer = parts[0]
+		ret.Account = strings.TrimSpace(parts[1])
+	} else {
+		ret.Account = label
+	}
+	if issuer := query.Get("issuer"); issuer != "" {
+		ret.Issuer = issuer
+	}
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		ret.Algorithm = strings.ToUpper(algorithm)
+	}
+	if digits := query.Get("digits"); digits != "" {
+		if ret.Digits, err = strconv.Atoi(digits); err != nil {
+			return nil, fmt.Errorf("Invalid digits '%s': %s", digits, err)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		seconds, err := strconv.Atoi(period)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid period '%s': %s", period, err)
+		}
+		ret.Period = time.Duration(seconds) * time.Second
+	}
+	if err = ret.validate(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// URI return the otpauth URI of the TOTP.
+func (t *TOTP) URI() string {
+	query := url.Values{}
+	query.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(t.Secret))
+	if t.Issuer != "" {
+		query.Set("issuer", t.Issuer)
+	}
+	query.Set("algorithm", t.algorithm())
+	query.Set("digits", strconv.Itoa(t.digits()))
+	query.Set("period", strconv.Itoa(int(t.period()/time.Second)))
+
+	label := t.Account
+	if t.Issuer != "" {
+		label = t.Issuer + ":" + t.Account
+	}
+	u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + label, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// Now return the code valid now.
+func (t *TOTP) Now() (string, error) {
+	return t.Code(time.Now())
+}
+
+// Code return the code valid at the time given.
+func (t *TOTP) Code(at time.Time) (string, error) {
+	if err := t.validate(); err != nil {
+		return "", err
+	}
+	counter := uint64(at.Unix() / int64(t.period()/time.Second))
+	return hotp(t.newHash, t.Secret, counter, t.digits()), nil
+}
+
+// validate return an error if the TOTP parameters are not supported.
+func (t *TOTP) validate() error {
+	if len(t.Secret) == 0 {
+		return fmt.Errorf("TOTP secret is empty")
+	}
+	switch t.algorithm() {
+	case TOTPAlgorithmSHA1, TOTPAlgorithmSHA256, TOTPAlgorithmSHA512:
+	default:
+		return fmt.Errorf("Unsupported TOTP algorithm '%s'", t.Algorithm)
+	}
+	if digits := t.digits(); digits < 6 || digits > 10 {
+		return fmt.Errorf("Invalid TOTP digits %d. It must be between 6 and 10", digits)
+	}
+	if t.period() < time.Second {
+		return fmt.Errorf("Invalid TOTP period %s", t.Period)
+	}
+	return nil
+}
+
+func (t *TOTP) algorithm() string {
+	if t.Algorithm == "" {
+		return TOTPAlgorithmSHA1
+	}
+	return strings.ToUpper(t.Algorithm)
+}
+
+func (t *TOTP) digits() int {
+	if t.Digits == 0 {
+		return 6
+	}
+	return t.Digits
+}
+
+func (t *TOTP) period() time.Duration {
+	if t.Period == 0 {
+		return 30 * time.Second
+	}
+	return t.Period
+}
+
+// newHash creates the hash of the TOTP algorithm.
+func (t *TOTP) newHash() hash.Hash {
+	switch t.algorithm() {
+	case TOTPAlgorithmSHA256:
+		return sha256.New()
+	case TOTPAlgorithmSHA512:
+		return sha512.New()
+	}
+	return sha1.New()
+}
+
+// hotp return the HMAC-based One-Time Password of the counter (RFC 4226), zero padded to digits.
+func hotp(newHash func() hash.Hash, secret []byte, counter uint64, digits int) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+
+	mac := hmac.New(newHash, secret)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	code := uint64(binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff)
+
+	modulo := uint64(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, code%modulo)
+}
+
+// decodeTOTPSecret decodes a base32 secret. Spaces, padding and lower case are accepted.
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return nil, fmt.Errorf("TOTP secret is empty")
+	}
+	ret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid TOTP secret: %s", err)
+	}
+	return ret, nil
+}
+
+// OTPSource provides OTP codes to Service.SAMLAuthenticateOTP without user interaction. See Service.SetOTPSource.
+type OTPSource interface {
+	// OTP return the code for the device, and false if the source does not handle this device.
+	OTP(device api.SAMLAssertionDevice) (string, bool, error)
+}
+
+// DefaultTOTPDeviceTypes are the device types handled by a TOTPSource without device types: the authenticator
+// applications generating TOTP codes.
+var DefaultTOTPDeviceTypes = []string{DeviceTypeGoogleAuthenticator, DeviceTypeOneLoginProtect}
+
+// TOTPSource is an OTPSource generating the codes with a TOTP, for the devices of the types given.
+type TOTPSource struct {
+	TOTP *TOTP
+	// DeviceTypes are the device types handled. DefaultTOTPDeviceTypes if empty.
+	DeviceTypes []string
+}
+
+// NewTOTPSource creates a TOTPSource. Without device types, it handles the DefaultTOTPDeviceTypes.
+func NewTOTPSource(totp *TOTP, deviceTypes ...string) (ret *TOTPSource) {
+	ret = new(TOTPSource)
+	ret.TOTP = totp
+	ret.DeviceTypes = deviceTypes
+	return
+}
+
+// OTP return the current TOTP code, if the device type is handled.
+func (s *TOTPSource) OTP(device api.SAMLAssertionDevice) (string, bool, error) {
+	if s == nil || s.TOTP == nil {
+		return "", false, nil
+	}
+	deviceTypes := s.DeviceTypes
+	if len(deviceTypes) == 0 {
+		deviceTypes = DefaultTOTPDeviceTypes
+	}
+	if !inStrings(deviceTypes, device.DeviceType) {
+		return "", false, nil
+	}
+	code, err := s.TOTP.Now()
+	return code, true, err
+}
+
+// inStrings return true if value is in list.
+func inStrings(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
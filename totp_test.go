@@ -0,0 +1,110 @@
+package onelogin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/clarsonneur/onelogin/api"
+)
+
+// TestTOTPCode checks the test vectors of RFC 6238, appendix B.
+func TestTOTPCode(t *testing.T) {
+	seeds := map[string]string{
+		TOTPAlgorithmSHA1:   "12345678901234567890",
+		TOTPAlgorithmSHA256: "12345678901234567890123456789012",
+		TOTPAlgorithmSHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	for _, test := range []struct {
+		time      int64
+		algorithm string
+		want      string
+	}{
+		{59, TOTPAlgorithmSHA1, "94287082"},
+		{59, TOTPAlgorithmSHA256, "46119246"},
+		{59, TOTPAlgorithmSHA512, "90693936"},
+		{1111111109, TOTPAlgorithmSHA1, "07081804"},
+		{1111111109, TOTPAlgorithmSHA256, "68084774"},
+		{1111111109, TOTPAlgorithmSHA512, "25091201"},
+		{1111111111, TOTPAlgorithmSHA1, "14050471"},
+		{1111111111, TOTPAlgorithmSHA256, "67062674"},
+		{1111111111, TOTPAlgorithmSHA512, "99943326"},
+		{1234567890, TOTPAlgorithmSHA1, "89005924"},
+		{1234567890, TOTPAlgorithmSHA256, "91819424"},
+		{1234567890, TOTPAlgorithmSHA512, "93441116"},
+		{2000000000, TOTPAlgorithmSHA1, "69279037"},
+		{2000000000, TOTPAlgorithmSHA256, "90698825"},
+		{2000000000, TOTPAlgorithmSHA512, "38618901"},
+		{20000000000, TOTPAlgorithmSHA1, "65353130"},
+		{20000000000, TOTPAlgorithmSHA256, "77737706"},
+		{20000000000, TOTPAlgorithmSHA512, "47863826"},
+	} {
+		totp := &TOTP{Secret: []byte(seeds[test.algorithm]), Algorithm: test.algorithm, Digits: 8}
+		code, err := totp.Code(time.Unix(test.time, 0))
+		if err != nil {
+			t.Errorf("%s at %d: %s", test.algorithm, test.time, err)
+			continue
+		}
+		if code != test.want {
+			t.Errorf("%s at %d: code = %s, want %s", test.algorithm, test.time, code, test.want)
+		}
+	}
+}
+
+func TestParseTOTPURI(t *testing.T) {
+	totp, err := ParseTOTPURI("otpauth://totp/OneLogin:me@myCompany.com?secret=gezd%20gnbv&issuer=OneLogin&digits=8&period=60&algorithm=sha256")
+	if err != nil {
+		t.Fatalf("ParseTOTPURI: %s", err)
+	}
+	if string(totp.Secret) != "12345" || totp.Issuer != "OneLogin" || totp.Account != "me@myCompany.com" ||
+		totp.Algorithm != TOTPAlgorithmSHA256 || totp.Digits != 8 || totp.Period != time.Minute {
+		t.Errorf("totp = %+v", totp)
+	}
+
+	again, err := ParseTOTPURI(totp.URI())
+	if err != nil {
+		t.Fatalf("ParseTOTPURI(URI()): %s", err)
+	}
+	if again.URI() != totp.URI() {
+		t.Errorf("URI = %s, want %s", again.URI(), totp.URI())
+	}
+
+	for _, uri := range []string{
+		"otpauth://hotp/OneLogin:me?secret=GEZDGNBV",
+		"otpauth://totp/OneLogin:me",
+		"otpauth://totp/OneLogin:me?secret=not-base32!",
+		"otpauth://totp/OneLogin:me?secret=GEZDGNBV&digits=4",
+		"otpauth://totp/OneLogin:me?secret=GEZDGNBV&algorithm=MD5",
+	} {
+		if _, err = ParseTOTPURI(uri); err == nil {
+			t.Errorf("%s: no error", uri)
+		}
+	}
+}
+
+func TestTOTPSourceDeviceTypes(t *testing.T) {
+	totp, err := NewTOTP("GEZDGNBV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, source := range []*TOTPSource{NewTOTPSource(totp), {TOTP: totp}} {
+		for deviceType, want := range map[string]bool{
+			DeviceTypeGoogleAuthenticator: true,
+			DeviceTypeOneLoginProtect:     true,
+			DeviceTypeOneLoginSMS:         false,
+			DeviceTypeYubiKey:             false,
+		} {
+			code, handled, err := source.OTP(api.SAMLAssertionDevice{DeviceID: 1, DeviceType: deviceType})
+			if err != nil || handled != want || (handled && len(code) != 6) {
+				t.Errorf("%s: OTP = %s, %t, %v, want handled %t", deviceType, code, handled, err, want)
+			}
+		}
+	}
+
+	source := NewTOTPSource(totp, DeviceTypeYubiKey)
+	if _, handled, _ := source.OTP(api.SAMLAssertionDevice{DeviceType: DeviceTypeGoogleAuthenticator}); handled {
+		t.Error("device type not configured handled")
+	}
+	if _, handled, _ := source.OTP(api.SAMLAssertionDevice{DeviceType: DeviceTypeYubiKey}); !handled {
+		t.Error("device type configured not handled")
+	}
+}